@@ -0,0 +1,151 @@
+// cli/ask.go
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// AskOptions configures a single non-interactive prompt sent by Ask.
+type AskOptions struct {
+	// ConfigPath is the configuration file that defines the hosts.
+	ConfigPath string
+	// Host is the name or URL of the configured host to query.
+	Host string
+	// Model is the model to query. When empty, the host's first model is used.
+	Model string
+	// Prompt is the user message to send.
+	Prompt string
+	// System overrides the host's system prompt when not empty.
+	System string
+	// JSON requests Ollama's JSON output format.
+	JSON bool
+	// Params are generation parameters given as key=value pairs, for example
+	// "temperature=0.2". They override the host's configured parameters.
+	Params []string
+	// Stats prints the response metadata to the error writer when the answer
+	// is complete.
+	Stats bool
+}
+
+// Ask sends a one-shot prompt to a configured host and streams the answer to
+// out. It uses the same request builder as the interactive chat, so system
+// prompts, parameters, schemas and think settings from the configuration
+// apply. Response metadata is written to errOut as JSON when opts.Stats is set.
+func Ask(ctx context.Context, opts AskOptions, out, errOut io.Writer) error {
+	cfg, err := loadConfig(opts.ConfigPath)
+	if err != nil {
+		return err
+	}
+
+	host, err := findHost(cfg, opts.Host)
+	if err != nil {
+		return err
+	}
+	modelName := opts.Model
+	if modelName == "" {
+		if len(host.Models) == 0 {
+			return fmt.Errorf("host %s has no models configured; use --model", host.Name)
+		}
+		modelName = host.Models[0]
+	}
+	if strings.TrimSpace(opts.Prompt) == "" {
+		return fmt.Errorf("prompt is empty")
+	}
+
+	r := newChatRequest(cfg, host, modelName, []chatMessage{{Role: "user", Content: opts.Prompt}})
+	if opts.System != "" {
+		r.systemPrompt = opts.System
+	}
+	if opts.JSON {
+		r.json = true
+	}
+	if r.parameters, err = parseParams(r.parameters, opts.Params); err != nil {
+		return err
+	}
+
+	resp, err := postChat(ctx, host, r, http.DefaultClient)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var splitter thinkSplitter
+	var writeErr error
+	write := func(s string) {
+		if writeErr == nil && s != "" {
+			_, writeErr = io.WriteString(out, s)
+		}
+	}
+	final, err := decodeChatStream(resp.Body, func(chunk streamChunk) {
+		answer, _ := splitter.feed(chunk.Message.Content)
+		write(answer)
+	})
+	answer, _ := splitter.flush()
+	write(answer)
+	write("\n")
+	if err != nil {
+		return fmt.Errorf("error reading response stream: %w", err)
+	}
+	if writeErr != nil {
+		return writeErr
+	}
+
+	if opts.Stats {
+		b, _ := json.MarshalIndent(metaFromChunk(final), "", "  ")
+		fmt.Fprintln(errOut, string(b))
+	}
+	return nil
+}
+
+// findHost returns the configured host whose name or URL matches nameOrURL.
+// When nameOrURL is empty, the first host is used.
+func findHost(cfg *Config, nameOrURL string) (Host, error) {
+	if nameOrURL == "" {
+		return cfg.Hosts[0], nil
+	}
+	for _, h := range cfg.Hosts {
+		if strings.EqualFold(h.Name, nameOrURL) || strings.TrimSuffix(h.URL, "/") == strings.TrimSuffix(nameOrURL, "/") {
+			return h, nil
+		}
+	}
+	return Host{}, fmt.Errorf("host %q not found in config", nameOrURL)
+}
+
+// parseParams applies key=value overrides to base. Keys are the JSON names of
+// the Parameters fields, such as temperature or num_ctx.
+func parseParams(base Parameters, kvs []string) (Parameters, error) {
+	if len(kvs) == 0 {
+		return base, nil
+	}
+
+	fields := map[string]any{}
+	b, _ := json.Marshal(base)
+	json.Unmarshal(b, &fields)
+
+	for _, kv := range kvs {
+		key, value, ok := strings.Cut(kv, "=")
+		if !ok || key == "" {
+			return base, fmt.Errorf("invalid parameter %q: expected key=value", kv)
+		}
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return base, fmt.Errorf("invalid value for parameter %s: %q is not a number", key, value)
+		}
+		fields[key] = n
+	}
+
+	b, _ = json.Marshal(fields)
+	dec := json.NewDecoder(strings.NewReader(string(b)))
+	dec.DisallowUnknownFields()
+	var params Parameters
+	if err := dec.Decode(&params); err != nil {
+		return base, fmt.Errorf("invalid parameters: %w", err)
+	}
+	return params, nil
+}
@@ -0,0 +1,72 @@
+// cli/ask_test.go
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAskStreamsAnswer(t *testing.T) {
+	var got map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+		fmt.Fprintln(w, `{"model":"m1","message":{"role":"assistant","content":"<think>hmm</think>Hel"},"done":false}`)
+		fmt.Fprintln(w, `{"model":"m1","message":{"role":"assistant","content":"lo"},"done":false}`)
+		fmt.Fprintln(w, `{"model":"m1","message":{"role":"assistant","content":""},"done":true,"eval_count":2}`)
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	cfg := fmt.Sprintf(`{"hosts":[{"name":"HostA","url":%q,"models":["m1"],"systemprompt":"be brief","parameters":{"top_k":10}}]}`, srv.URL)
+	if err := os.WriteFile(path, []byte(cfg), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out, errOut bytes.Buffer
+	opts := AskOptions{ConfigPath: path, Host: "hosta", Prompt: "hi", System: "be terse", JSON: true, Params: []string{"temperature=0.2"}, Stats: true}
+	if err := Ask(context.Background(), opts, &out, &errOut); err != nil {
+		t.Fatalf("Ask returned error: %v", err)
+	}
+
+	if out.String() != "Hello\n" {
+		t.Errorf("unexpected answer: %q", out.String())
+	}
+	if !strings.Contains(errOut.String(), `"eval_count": 2`) {
+		t.Errorf("expected stats on stderr, got %q", errOut.String())
+	}
+	if got["model"] != "m1" || got["format"] != "json" {
+		t.Errorf("unexpected request payload: %v", got)
+	}
+	if msgs := got["messages"].([]any); msgs[0].(map[string]any)["content"] != "be terse" {
+		t.Errorf("expected system prompt override, got %v", msgs[0])
+	}
+	options := got["options"].(map[string]any)
+	if options["temperature"] != 0.2 || options["top_k"] != float64(10) {
+		t.Errorf("expected merged parameters, got %v", options)
+	}
+}
+
+func TestParseParams(t *testing.T) {
+	topK := 5
+	params, err := parseParams(Parameters{TopK: &topK}, []string{"num_ctx=4096", "top_p=0.9"})
+	if err != nil {
+		t.Fatalf("parseParams returned error: %v", err)
+	}
+	if *params.TopK != 5 || *params.NumCtx != 4096 || *params.TopP != 0.9 {
+		t.Errorf("unexpected parameters: %+v", params)
+	}
+
+	for _, bad := range []string{"temperature", "bogus=1", "top_k=high", "num_ctx=1.5"} {
+		if _, err := parseParams(Parameters{}, []string{bad}); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+// cli/attach.go
+package cli
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// maxTextAttachmentBytes caps text files inlined into a message.
+	maxTextAttachmentBytes = 64 * 1024
+	// maxImageAttachmentBytes caps images sent to multimodal models.
+	maxImageAttachmentBytes = 10 * 1024 * 1024
+)
+
+// imageExtensions lists file extensions sent as images rather than text.
+var imageExtensions = map[string]bool{
+	".png":  true,
+	".jpg":  true,
+	".jpeg": true,
+	".gif":  true,
+	".webp": true,
+	".bmp":  true,
+}
+
+// attachment is a file queued with /attach for the next user message.
+// Exactly one of text or image is set.
+type attachment struct {
+	// name is the base file name shown in the UI.
+	name string
+	// text holds the contents of a text file.
+	text string
+	// image holds the base64-encoded contents of an image file.
+	image string
+}
+
+// loadAttachment reads the file at path and prepares it for sending. Images
+// are base64-encoded for Ollama's images field; other files must be valid
+// UTF-8 text. Files over the size limits are rejected.
+func loadAttachment(path string) (attachment, error) {
+	if strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, path[2:])
+		}
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return attachment{}, fmt.Errorf("could not attach file: %w", err)
+	}
+	if info.IsDir() {
+		return attachment{}, fmt.Errorf("could not attach %s: is a directory", path)
+	}
+
+	name := filepath.Base(path)
+	isImage := imageExtensions[strings.ToLower(filepath.Ext(path))]
+	limit := int64(maxTextAttachmentBytes)
+	if isImage {
+		limit = maxImageAttachmentBytes
+	}
+	if info.Size() > limit {
+		return attachment{}, fmt.Errorf("could not attach %s: %d bytes exceeds the %d byte limit", name, info.Size(), limit)
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return attachment{}, fmt.Errorf("could not attach file: %w", err)
+	}
+
+	if isImage {
+		return attachment{name: name, image: base64.StdEncoding.EncodeToString(b)}, nil
+	}
+	if !utf8.Valid(b) || bytes.IndexByte(b, 0) >= 0 {
+		return attachment{}, fmt.Errorf("could not attach %s: not a text or image file", name)
+	}
+	return attachment{name: name, text: string(b)}, nil
+}
+
+// attachToMessage inlines text attachments into msg as fenced code blocks and
+// adds image attachments to its images field.
+func attachToMessage(msg chatMessage, atts []attachment) chatMessage {
+	var content strings.Builder
+	content.WriteString(msg.Content)
+	for _, a := range atts {
+		msg.attachments = append(msg.attachments, a.name)
+		if a.image != "" {
+			msg.Images = append(msg.Images, a.image)
+			continue
+		}
+		fence := "```"
+		for strings.Contains(a.text, fence) {
+			fence += "`"
+		}
+		lang := strings.TrimPrefix(filepath.Ext(a.name), ".")
+		content.WriteString(fmt.Sprintf("\n\nFile: %s\n%s%s\n%s\n%s", a.name, fence, lang, strings.TrimRight(a.text, "\n"), fence))
+	}
+	msg.Content = content.String()
+	return msg
+}
+
+// imageAttachments returns the images of msg as attachments, so an edited
+// message can be sent with them again. Names are taken from the message's
+// attachments with an image extension, in order.
+func imageAttachments(msg chatMessage) []attachment {
+	var names []string
+	for _, name := range msg.attachments {
+		if imageExtensions[strings.ToLower(filepath.Ext(name))] {
+			names = append(names, name)
+		}
+	}
+	atts := make([]attachment, len(msg.Images))
+	for i, image := range msg.Images {
+		name := fmt.Sprintf("image %d", i+1)
+		if i < len(names) {
+			name = names[i]
+		}
+		atts[i] = attachment{name: name, image: image}
+	}
+	return atts
+}
+
+// attachmentNames returns the names of atts for display.
+func attachmentNames(atts []attachment) []string {
+	names := make([]string, len(atts))
+	for i, a := range atts {
+		names[i] = a.name
+	}
+	return names
+}
@@ -0,0 +1,99 @@
+// cli/attach_test.go
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestLoadAttachment(t *testing.T) {
+	dir := t.TempDir()
+
+	textPath := filepath.Join(dir, "notes.go")
+	if err := os.WriteFile(textPath, []byte("package main\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	imagePath := filepath.Join(dir, "cat.png")
+	if err := os.WriteFile(imagePath, []byte{0x89, 'P', 'N', 'G', 0}, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	binaryPath := filepath.Join(dir, "blob.bin")
+	if err := os.WriteFile(binaryPath, []byte{0, 1, 2}, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	bigPath := filepath.Join(dir, "big.txt")
+	if err := os.WriteFile(bigPath, []byte(strings.Repeat("a", maxTextAttachmentBytes+1)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	text, err := loadAttachment(textPath)
+	if err != nil || text.text == "" || text.image != "" {
+		t.Fatalf("expected text attachment; got %+v, err=%v", text, err)
+	}
+	image, err := loadAttachment(imagePath)
+	if err != nil || image.image == "" || image.text != "" {
+		t.Fatalf("expected image attachment; got %+v, err=%v", image, err)
+	}
+	if _, err := loadAttachment(binaryPath); err == nil {
+		t.Error("expected binary file to be rejected")
+	}
+	if _, err := loadAttachment(bigPath); err == nil {
+		t.Error("expected oversized text file to be rejected")
+	}
+	if _, err := loadAttachment(dir); err == nil {
+		t.Error("expected directory to be rejected")
+	}
+
+	msg := attachToMessage(chatMessage{Role: "user", Content: "review this"}, []attachment{text, image})
+	if !strings.Contains(msg.Content, "File: notes.go\n```go\npackage main\n```") {
+		t.Errorf("expected fenced text attachment in content, got %q", msg.Content)
+	}
+	if len(msg.Images) != 1 || msg.Images[0] != image.image {
+		t.Errorf("expected one image in message, got %v", msg.Images)
+	}
+	if strings.Join(msg.attachments, ",") != "notes.go,cat.png" {
+		t.Errorf("expected attachment names to be recorded, got %v", msg.attachments)
+	}
+}
+
+func TestEditKeepsImages(t *testing.T) {
+	cfg := &Config{Hosts: []Host{{Name: "HostA", URL: "http://x", Models: []string{"m1"}}}}
+	m := initialModel(cfg)
+	m.state = viewChat
+	m.program = &tea.Program{}
+
+	m.session.append(attachToMessage(chatMessage{Role: "user", Content: "what is this?"}, []attachment{
+		{name: "notes.txt", text: "hi"},
+		{name: "cat.png", image: "aW1n"},
+	}))
+	m.session.append(chatMessage{Role: "assistant", Content: "a cat"})
+	m.chatHistory = m.session.path()
+	send := func(input string) {
+		t.Helper()
+		m.textArea.SetValue(input)
+		m2, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		m = m2.(*model)
+	}
+
+	send("/edit")
+	if len(m.attachments) != 1 || m.attachments[0].name != "cat.png" || !strings.Contains(m.notice, "1 image(s)") {
+		t.Fatalf("expected the image queued for the edit; attachments=%v notice=%q", m.attachments, m.notice)
+	}
+	send("/edit cancel")
+	if len(m.attachments) != 0 {
+		t.Fatalf("expected cancelling to drop the carried image, got %v", m.attachments)
+	}
+
+	send("/edit")
+	send("what is this animal?")
+	if got := m.chatHistory[0]; got.Content != "what is this animal?" || len(got.Images) != 1 || got.Images[0] != "aW1n" {
+		t.Fatalf("expected the edited message to keep its image, got %+v", got)
+	}
+	if len(m.attachments) != 0 {
+		t.Fatalf("expected no pending attachments after sending, got %v", m.attachments)
+	}
+}
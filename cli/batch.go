@@ -0,0 +1,359 @@
+// cli/batch.go
+package cli
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// BatchOptions configures a batch run started by RunBatch.
+type BatchOptions struct {
+	// ConfigPath is the configuration file that defines the hosts.
+	ConfigPath string
+	// PromptsPath is the JSONL file of prompts to run.
+	PromptsPath string
+	// OutPath is the JSONL file results are appended to. When empty, it is
+	// derived from PromptsPath, e.g. prompts.results.jsonl.
+	OutPath string
+	// Hosts selects hosts by name or URL. When empty, all hosts are used.
+	Hosts []string
+	// Models selects the models to run on every selected host. When empty,
+	// each host's configured models are used.
+	Models []string
+	// Concurrency is the maximum number of requests in flight per host URL.
+	Concurrency int
+}
+
+// BatchPrompt is one line of a batch prompts file.
+type BatchPrompt struct {
+	// ID identifies the prompt in results; it defaults to the line number.
+	ID string `json:"id"`
+	// Prompt is the user message to send.
+	Prompt string `json:"prompt"`
+	// System overrides the host's system prompt when not empty.
+	System string `json:"system,omitempty"`
+}
+
+// BatchResult is one line of a batch results file.
+type BatchResult struct {
+	// ID is the id of the prompt.
+	ID string `json:"id"`
+	// Host is the name of the host that answered.
+	Host string `json:"host"`
+	// Model is the model that answered.
+	Model string `json:"model"`
+	// Prompt is the prompt that was sent.
+	Prompt string `json:"prompt"`
+	// Response is the full answer text, without any reasoning.
+	Response string `json:"response"`
+	// Thinking holds the model's reasoning, if it reported any.
+	Thinking string `json:"thinking,omitempty"`
+	// Error describes why the request failed; failed requests are retried
+	// when the batch is resumed.
+	Error string `json:"error,omitempty"`
+	// StartedAt is when the request was sent.
+	StartedAt time.Time `json:"started_at"`
+	// Meta holds the timing and token metrics of the response.
+	Meta LLMResponseMeta `json:"meta"`
+}
+
+// batchJob is a single prompt to run against one host and model.
+type batchJob struct {
+	prompt BatchPrompt
+	host   Host
+	model  string
+}
+
+// key identifies the job for resuming.
+func (j batchJob) key() string {
+	return batchKey(j.prompt.ID, j.host.Name, j.model)
+}
+
+func batchKey(id, host, model string) string {
+	return id + "\x00" + host + "\x00" + model
+}
+
+// RunBatch sends every prompt in opts.PromptsPath to every selected host and
+// model and appends one BatchResult per pair to the results file. Pairs that
+// already completed without error in an existing results file are skipped, so
+// an interrupted run can be resumed by running it again. Progress is written
+// to progress.
+func RunBatch(ctx context.Context, opts BatchOptions, progress io.Writer) error {
+	cfg, err := loadConfig(opts.ConfigPath)
+	if err != nil {
+		return err
+	}
+	prompts, err := readBatchPrompts(opts.PromptsPath)
+	if err != nil {
+		return err
+	}
+
+	hosts := cfg.Hosts
+	if len(opts.Hosts) > 0 {
+		// A host given both by name and by URL is only run once.
+		hosts = nil
+		seen := map[string]bool{}
+		for _, name := range opts.Hosts {
+			h, err := findHost(cfg, name)
+			if err != nil {
+				return err
+			}
+			if !seen[h.Name] {
+				seen[h.Name] = true
+				hosts = append(hosts, h)
+			}
+		}
+	}
+
+	outPath := opts.OutPath
+	if outPath == "" {
+		outPath = strings.TrimSuffix(opts.PromptsPath, ".jsonl") + ".results.jsonl"
+	}
+	done, err := completedBatchKeys(outPath)
+	if err != nil {
+		return err
+	}
+
+	// Jobs are grouped by URL, so config entries for the same server share
+	// its concurrency limit.
+	jobsByHost := map[string][]batchJob{}
+	total, skipped := 0, 0
+	for _, h := range hosts {
+		models := opts.Models
+		if len(models) == 0 {
+			models = h.Models
+		}
+		for _, p := range prompts {
+			for _, m := range models {
+				job := batchJob{prompt: p, host: h, model: m}
+				if done[job.key()] {
+					skipped++
+					continue
+				}
+				url := strings.TrimSuffix(h.URL, "/")
+				jobsByHost[url] = append(jobsByHost[url], job)
+				total++
+			}
+		}
+	}
+	fmt.Fprintf(progress, "%d requests to run, %d already completed, results in %s\n", total, skipped, outPath)
+	if total == 0 {
+		return nil
+	}
+
+	f, err := os.OpenFile(outPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("could not open results file: %w", err)
+	}
+	defer f.Close()
+	if err := terminateLastLine(f); err != nil {
+		return err
+	}
+
+	concurrency := opts.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var (
+		mu       sync.Mutex
+		finished int
+		failed   int
+		writeErr error
+		wg       sync.WaitGroup
+	)
+	record := func(res BatchResult) {
+		mu.Lock()
+		defer mu.Unlock()
+		b, _ := json.Marshal(res)
+		if _, err := f.Write(append(b, '\n')); err != nil && writeErr == nil {
+			writeErr = fmt.Errorf("could not write results file: %w", err)
+		}
+		finished++
+		status := "ok"
+		if res.Error != "" {
+			failed++
+			status = "error: " + res.Error
+		}
+		fmt.Fprintf(progress, "[%d/%d] %s %s/%s %s\n", finished, total, res.ID, res.Host, res.Model, status)
+	}
+
+	for _, jobs := range jobsByHost {
+		queue := make(chan batchJob)
+		for w := 0; w < concurrency; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for job := range queue {
+					record(runBatchJob(ctx, cfg, job))
+				}
+			}()
+		}
+		go func(jobs []batchJob) {
+			defer close(queue)
+			for _, job := range jobs {
+				select {
+				case queue <- job:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}(jobs)
+	}
+	wg.Wait()
+
+	if writeErr != nil {
+		return writeErr
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d requests failed; run again to retry them", failed, total)
+	}
+	return nil
+}
+
+// runBatchJob sends a single prompt and collects the full response.
+func runBatchJob(ctx context.Context, cfg *Config, job batchJob) BatchResult {
+	res := BatchResult{
+		ID:        job.prompt.ID,
+		Host:      job.host.Name,
+		Model:     job.model,
+		Prompt:    job.prompt.Prompt,
+		StartedAt: time.Now(),
+	}
+
+	r := newChatRequest(cfg, job.host, job.model, []chatMessage{{Role: "user", Content: job.prompt.Prompt}})
+	if job.prompt.System != "" {
+		r.systemPrompt = job.prompt.System
+	}
+
+	resp, err := postChat(ctx, job.host, r, http.DefaultClient)
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	defer resp.Body.Close()
+
+	var splitter thinkSplitter
+	var answer, thinking strings.Builder
+	final, err := decodeChatStream(resp.Body, func(chunk streamChunk) {
+		thinking.WriteString(chunk.Message.Thinking)
+		a, t := splitter.feed(chunk.Message.Content)
+		answer.WriteString(a)
+		thinking.WriteString(t)
+	})
+	a, t := splitter.flush()
+	answer.WriteString(a)
+	thinking.WriteString(t)
+
+	res.Response = answer.String()
+	res.Thinking = strings.TrimSpace(thinking.String())
+	res.Meta = metaFromChunk(final)
+	if err != nil {
+		res.Error = err.Error()
+	} else if !final.Done {
+		res.Error = "stream ended before the response was complete"
+	}
+	return res
+}
+
+// readBatchPrompts reads a JSONL prompts file. Blank lines are skipped and
+// prompts without an id are named after their line number.
+func readBatchPrompts(path string) ([]BatchPrompt, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not open prompts file: %w", err)
+	}
+	defer f.Close()
+
+	var prompts []BatchPrompt
+	seen := map[string]bool{}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var p BatchPrompt
+		if err := json.Unmarshal([]byte(text), &p); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		if p.Prompt == "" {
+			return nil, fmt.Errorf("%s:%d: prompt is empty", path, line)
+		}
+		if p.ID == "" {
+			p.ID = fmt.Sprintf("line-%d", line)
+		}
+		if seen[p.ID] {
+			return nil, fmt.Errorf("%s:%d: duplicate id %q", path, line, p.ID)
+		}
+		seen[p.ID] = true
+		prompts = append(prompts, p)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("could not read prompts file: %w", err)
+	}
+	return prompts, nil
+}
+
+// completedBatchKeys returns the jobs recorded without error in an existing
+// results file. A missing file means nothing has completed yet.
+func completedBatchKeys(path string) (map[string]bool, error) {
+	done := map[string]bool{}
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return done, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not open results file: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+		var res BatchResult
+		// A partially written last line from an interrupted run is ignored.
+		if err := json.Unmarshal(scanner.Bytes(), &res); err != nil {
+			continue
+		}
+		if res.Error == "" {
+			done[batchKey(res.ID, res.Host, res.Model)] = true
+		}
+	}
+	return done, scanner.Err()
+}
+
+// terminateLastLine appends a newline to f when its last line was cut short
+// by an interrupted run, so new results start on a line of their own.
+func terminateLastLine(f *os.File) error {
+	info, err := f.Stat()
+	if err != nil || info.Size() == 0 {
+		return err
+	}
+	last := make([]byte, 1)
+	r, err := os.Open(f.Name())
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	if _, err := r.ReadAt(last, info.Size()-1); err != nil {
+		return err
+	}
+	if last[0] != '\n' {
+		_, err = f.Write([]byte{'\n'})
+	}
+	return err
+}
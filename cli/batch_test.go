@@ -0,0 +1,160 @@
+// cli/batch_test.go
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunBatchResumes(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		requests = map[string]int{}
+		inFlight atomic.Int32
+		maxSeen  atomic.Int32
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			old := maxSeen.Load()
+			if n <= old || maxSeen.CompareAndSwap(old, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		var body struct {
+			Model    string        `json:"model"`
+			Messages []chatMessage `json:"messages"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		prompt := body.Messages[len(body.Messages)-1].Content
+		mu.Lock()
+		requests[prompt+"/"+body.Model]++
+		mu.Unlock()
+
+		if prompt == "fail" {
+			http.Error(w, "boom", http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprintf(w, `{"model":%q,"message":{"content":"echo %s"},"done":true,"eval_count":3}`+"\n", body.Model, prompt)
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "config.json")
+	os.WriteFile(cfgPath, []byte(fmt.Sprintf(`{"hosts":[{"name":"HostA","url":%q,"models":["m1","m2"]}]}`, srv.URL)), 0o644)
+	promptsPath := filepath.Join(dir, "prompts.jsonl")
+	os.WriteFile(promptsPath, []byte("{\"id\":\"a\",\"prompt\":\"one\"}\n\n{\"prompt\":\"two\"}\n{\"id\":\"c\",\"prompt\":\"fail\"}\n"), 0o644)
+
+	opts := BatchOptions{ConfigPath: cfgPath, PromptsPath: promptsPath, Concurrency: 2}
+	var progress bytes.Buffer
+	if err := RunBatch(context.Background(), opts, &progress); err == nil {
+		t.Fatal("expected an error reporting the failed requests")
+	}
+	if maxSeen.Load() > 2 {
+		t.Errorf("expected at most 2 concurrent requests per host, saw %d", maxSeen.Load())
+	}
+
+	results := readResults(t, filepath.Join(dir, "prompts.results.jsonl"))
+	if len(results) != 6 {
+		t.Fatalf("expected 6 results, got %d", len(results))
+	}
+	byKey := map[string]BatchResult{}
+	for _, r := range results {
+		byKey[r.ID+"/"+r.Model] = r
+	}
+	if r := byKey["line-3/m2"]; r.Response != "echo two" || r.Meta.EvalCount != 3 || r.Host != "HostA" {
+		t.Errorf("unexpected result for line-3/m2: %+v", r)
+	}
+	if r := byKey["c/m1"]; r.Error == "" {
+		t.Errorf("expected failed result for c/m1, got %+v", r)
+	}
+
+	// A second run only retries the failed prompts.
+	progress.Reset()
+	RunBatch(context.Background(), opts, &progress)
+	if requests["one/m1"] != 1 || requests["fail/m1"] != 2 {
+		t.Errorf("expected completed prompts to be skipped, got %v", requests)
+	}
+}
+
+func readResults(t *testing.T, path string) []BatchResult {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var results []BatchResult
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var r BatchResult
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			t.Fatalf("invalid result line %q: %v", scanner.Text(), err)
+		}
+		results = append(results, r)
+	}
+	return results
+}
+
+func TestRunBatchDedupesHosts(t *testing.T) {
+	var requests, inFlight, maxInFlight atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			m := maxInFlight.Load()
+			if n <= m || maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		fmt.Fprintln(w, `{"model":"m1","message":{"content":"hi"},"done":true}`)
+	}))
+	defer srv.Close()
+
+	// Two config entries for the same server, as with personas.
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "config.json")
+	os.WriteFile(cfgPath, []byte(fmt.Sprintf(`{"hosts":[
+		{"name":"HostA","url":%q,"models":["m1"]},
+		{"name":"Pirate","url":%q,"models":["m1"],"systemprompt":"Talk like a pirate."}
+	]}`, srv.URL, srv.URL+"/")), 0o644)
+	promptsPath := filepath.Join(dir, "prompts.jsonl")
+	os.WriteFile(promptsPath, []byte(`{"id":"a","prompt":"one"}`+"\n"+`{"id":"b","prompt":"two"}`+"\n"), 0o644)
+
+	opts := BatchOptions{ConfigPath: cfgPath, PromptsPath: promptsPath, Hosts: []string{"HostA", "hosta", srv.URL + "/"}}
+	if err := RunBatch(context.Background(), opts, &bytes.Buffer{}); err != nil {
+		t.Fatalf("RunBatch: %v", err)
+	}
+	if n := requests.Load(); n != 2 {
+		t.Errorf("expected one request per prompt for a host given three times, got %d", n)
+	}
+
+	// Without --hosts every entry runs, one request at a time per server.
+	opts.Hosts = nil
+	if err := RunBatch(context.Background(), opts, &bytes.Buffer{}); err != nil {
+		t.Fatalf("RunBatch: %v", err)
+	}
+	results := readResults(t, filepath.Join(dir, "prompts.results.jsonl"))
+	if len(results) != 4 || results[2].Host != "Pirate" || results[3].Host != "Pirate" {
+		t.Errorf("expected both prompts for each config entry, got %+v", results)
+	}
+	if n := maxInFlight.Load(); n != 1 {
+		t.Errorf("expected entries sharing a URL to share the concurrency limit, got %d in flight", n)
+	}
+}
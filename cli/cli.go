@@ -30,10 +30,39 @@ type Config struct {
 	Hosts []Host `json:"hosts"`
 	// Debug enables display of timing metrics and logs additional details.
 	Debug bool `json:"debug"`
-	// Multimodel toggles the four-column chat interface for multiple models.
+	// Multimodel toggles the side-by-side chat interface for multiple models.
 	Multimodel bool `json:"multimodel"`
 	// JSON enables JSON output mode for CLI interactions.
 	JSON bool `json:"json"`
+	// ContextStrategy selects how chat history is trimmed when it nears the
+	// context window: "drop_oldest", "keep_last", "summarize", or empty to
+	// always send the full history.
+	ContextStrategy string `json:"context_strategy"`
+	// ContextKeepLast is the number of recent messages kept verbatim by the
+	// "keep_last" and "summarize" strategies.
+	ContextKeepLast int `json:"context_keep_last"`
+	// Think turns model reasoning on or off for models that support it.
+	// When unset, the model's default behavior is used.
+	Think *bool `json:"think,omitempty"`
+	// Tools offers the built-in local tools (read_file, list_directory,
+	// current_time, calculator) to models in single-model chat.
+	Tools bool `json:"tools"`
+	// SerializePerHost sends the requests of multimodel lanes that share a
+	// host one after another instead of concurrently.
+	SerializePerHost bool `json:"serialize_per_host"`
+	// Judge configures a model that scores multimodel answers.
+	Judge *JudgeConfig `json:"judge,omitempty"`
+	// VotesFile is the JSONL file multimodel votes are appended to. It
+	// defaults to votes.jsonl.
+	VotesFile string `json:"votes_file"`
+	// UnloadOnStart unloads every model on every host before multimodel
+	// mode starts, so lanes begin from a cold start.
+	UnloadOnStart bool `json:"unload_on_start"`
+	// Presets are named multimodel lane layouts that can be picked when
+	// multimodel mode starts or passed to chat --preset.
+	Presets map[string][]PresetLane `json:"presets,omitempty"`
+	// path is the file the config was loaded from, used to save presets.
+	path string
 }
 
 // Host describes a language model host and its configured models.
@@ -49,6 +78,11 @@ type Host struct {
 	// SystemPrompt sets a custom system prompt for all requests; when empty, the model's default is used.
 	SystemPrompt string     `json:"systemprompt"`
 	Parameters   Parameters `json:"parameters"`
+	// Schema constrains responses to a JSON Schema, given either inline as an
+	// object or as the path of a schema file. It is sent as Ollama's format.
+	Schema json.RawMessage `json:"schema,omitempty"`
+	// schema is the parsed form of Schema, resolved when the config is loaded.
+	schema map[string]any
 }
 
 // Parameters defines generation settings for a host.
@@ -64,6 +98,7 @@ type Parameters struct {
 	RepeatPenalty    *float64 `json:"repeat_penalty,omitempty"`
 	PresencePenalty  *float64 `json:"presence_penalty,omitempty"`
 	FrequencyPenalty *float64 `json:"frequency_penalty,omitempty"`
+	NumCtx           *int     `json:"num_ctx,omitempty"`
 }
 
 // loadConfig reads and parses the configuration file from the given path.
@@ -83,6 +118,27 @@ func loadConfig(path string) (*Config, error) {
 	if len(cfg.Hosts) == 0 {
 		return nil, errors.New("config must contain at least one host")
 	}
+	for i := range cfg.Hosts {
+		schema, err := loadSchema(cfg.Hosts[i].Schema)
+		if err != nil {
+			return nil, fmt.Errorf("host %s: %w", cfg.Hosts[i].Name, err)
+		}
+		cfg.Hosts[i].schema = schema
+	}
+	if cfg.Judge != nil {
+		host, err := findHost(&cfg, cfg.Judge.Host)
+		if err != nil {
+			return nil, fmt.Errorf("judge: %w", err)
+		}
+		if cfg.Judge.Model == "" {
+			return nil, errors.New("judge: model is required")
+		}
+		cfg.Judge.host = host
+	}
+	if err := validatePresets(&cfg); err != nil {
+		return nil, err
+	}
+	cfg.path = path
 	return &cfg, nil
 }
 
@@ -117,6 +173,16 @@ type chatMessage struct {
 	Role string `json:"role"`
 	// Content of the message.
 	Content string `json:"content"`
+	// Images holds base64-encoded images for multimodal models.
+	Images []string `json:"images,omitempty"`
+	// attachments lists the names of files attached to the message, for display.
+	attachments []string
+	// ToolCalls lists the tools an assistant message asked to run.
+	ToolCalls []toolCall `json:"tool_calls,omitempty"`
+	// ToolName names the tool whose result a "tool" message carries.
+	ToolName string `json:"tool_name,omitempty"`
+	// thinking holds the model's reasoning, shown in the UI but never sent back.
+	thinking string
 }
 
 // streamChunk represents a single chunk of a streaming language model response.
@@ -129,6 +195,10 @@ type streamChunk struct {
 		Role string `json:"role"`
 		// Partial content of the message.
 		Content string `json:"content"`
+		// Partial reasoning output for models that report it separately.
+		Thinking string `json:"thinking"`
+		// Tool calls requested by the model.
+		ToolCalls []toolCall `json:"tool_calls"`
 	} `json:"message"`
 	// Indicates if this is the final chunk of the stream.
 	Done bool `json:"done"`
@@ -204,10 +274,44 @@ type model struct {
 	viewport viewport.Model
 	// Bubble Tea spinner model for indicating loading.
 	spinner spinner.Model
-	// Stores the history of chat messages.
+	// Stores the history of chat messages on the active branch.
 	chatHistory []chatMessage
+	// Conversation tree holding every branch; chatHistory mirrors its active path.
+	session *sessionTree
+	// Depth of the assistant turn being regenerated, or -1 when appending.
+	forkDepth int
+	// Depth of the user turn being edited, or -1 when not editing.
+	editDepth int
+	// Number of leading attachments carried over from the edited message.
+	editImages int
+	// Feedback from the last chat command, shown above the input.
+	notice string
+	// Files queued with /attach for the next message.
+	attachments []attachment
+	// Tokens occupied by the last exchange (prompt plus reply).
+	contextUsed int
+	// Cumulative prompt tokens evaluated during this session.
+	sessionPromptTokens int
+	// Summary standing in for older messages under the summarize strategy.
+	contextSummary string
+	// Number of leading history messages covered by contextSummary.
+	summaryCovers int
 	// Buffer to accumulate streaming responses.
 	responseBuf strings.Builder
+	// Buffer to accumulate streaming reasoning output.
+	thinkingBuf strings.Builder
+	// Separates inline <think> blocks from the streamed answer.
+	splitter thinkSplitter
+	// Whether reasoning sections are expanded in the chat history.
+	showThinking bool
+	// Reasoning setting sent with each request; nil uses the model default.
+	think *bool
+	// Tool calls from the last reply that are waiting for confirmation.
+	pendingToolCalls []toolCall
+	// Schema set with /schema for this session; it overrides the host schema.
+	sessionSchema map[string]any
+	// Validation result of the last response against the active schema.
+	schemaCheck schemaResult
 	// Metadata of the last language model response.
 	responseMeta LLMResponseMeta
 	// The currently selected host.
@@ -264,6 +368,10 @@ func initialModel(cfg *Config) *model {
 		hostList:  hostList,
 		modelList: list.New(nil, list.NewDefaultDelegate(), 0, 0),
 		viewport:  vp,
+		session:   newSessionTree(),
+		forkDepth: -1,
+		editDepth: -1,
+		think:     cfg.Think,
 	}
 }
 
@@ -311,6 +419,12 @@ type chatReadyErr error
 // streamChunkMsg is sent when a new chunk of a streaming response is received.
 type streamChunkMsg string
 
+// streamThinkingMsg is sent when a chunk of reasoning output is received.
+type streamThinkingMsg string
+
+// streamToolCallsMsg is sent when the model requests tool calls.
+type streamToolCallsMsg []toolCall
+
 // streamEndMsg is sent when a streaming response has completed.
 type streamEndMsg struct{ meta LLMResponseMeta }
 
@@ -392,109 +506,194 @@ func getLoadedModels(host Host, client *http.Client) ([]string, error) {
 }
 
 // loadModelCmd is a Bubble Tea command that attempts to load a specified model
-// on the given host by sending a minimal generate request to /api/generate.
+// on the given host, see preloadModel.
 // This is typically used to ensure a model is ready for chat.
 // It returns a tea.Msg indicating success (chatReadyMsg) or failure (chatReadyErr).
 func loadModelCmd(host Host, modelName string, client *http.Client) tea.Cmd {
 	return func() tea.Msg {
-		payload := map[string]any{
-			"model":  modelName,
-			"prompt": ".",
-			"stream": false,
-		}
-		body, _ := json.Marshal(payload)
-		req, err := http.NewRequestWithContext(context.Background(), "POST", host.URL+"/api/generate", bytes.NewReader(body))
-		if err != nil {
+		if err := preloadModel(host, modelName, client); err != nil {
 			return chatReadyErr(err)
 		}
-		req.Header.Set("Content-Type", "application/json")
+		return chatReadyMsg{}
+	}
+}
 
-		resp, err := client.Do(req)
-		if err != nil {
-			return chatReadyErr(err)
-		}
+// preloadModel loads a model into memory on the host by sending a minimal
+// generate request to /api/generate. It returns once the model has answered.
+func preloadModel(host Host, modelName string, client *http.Client) error {
+	payload := map[string]any{
+		"model":  modelName,
+		"prompt": ".",
+		"stream": false,
+	}
+	body, _ := json.Marshal(payload)
+	req, err := http.NewRequestWithContext(context.Background(), "POST", host.URL+"/api/generate", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("API returned non-200 status: %s. Body: %s", resp.Status, string(bodyBytes))
+	}
+	return nil
+}
+
+// chatRequest collects the settings for a single /api/chat call. Both the
+// single-model and multimodel views build their payloads from it.
+type chatRequest struct {
+	// model is the model identifier to query.
+	model string
+	// history is the conversation to send, without the system prompt.
+	history []chatMessage
+	// systemPrompt is prepended as a system message when not empty.
+	systemPrompt string
+	// json requests Ollama's JSON output format.
+	json bool
+	// schema requests output matching a JSON Schema; it takes precedence over json.
+	schema map[string]any
+	// parameters are sent as the request options.
+	parameters Parameters
+	// think turns model reasoning on or off; nil leaves the model default.
+	think *bool
+	// tools are the tool definitions offered to the model.
+	tools []map[string]any
+}
+
+// newChatRequest builds the chat request for host using its system prompt and
+// parameters together with the global settings in cfg.
+func newChatRequest(cfg *Config, host Host, modelName string, history []chatMessage) chatRequest {
+	return chatRequest{
+		model:        modelName,
+		history:      history,
+		systemPrompt: host.SystemPrompt,
+		json:         cfg.JSON,
+		schema:       host.schema,
+		parameters:   host.Parameters,
+		think:        cfg.Think,
+	}
+}
+
+// payload returns the JSON body for a streaming /api/chat request.
+func (r chatRequest) payload() map[string]any {
+	messages := r.history
+	if r.systemPrompt != "" {
+		messages = append([]chatMessage{{Role: "system", Content: r.systemPrompt}}, messages...)
+	}
+
+	payload := map[string]any{
+		"model":    r.model,
+		"messages": messages,
+		"options":  r.parameters,
+		"stream":   true,
+	}
+	if r.schema != nil {
+		payload["format"] = r.schema
+	} else if r.json {
+		payload["format"] = "json"
+	}
+	if r.think != nil {
+		payload["think"] = *r.think
+	}
+	if len(r.tools) > 0 {
+		payload["tools"] = r.tools
+	}
+	return payload
+}
+
+// postChat sends r to the /api/chat endpoint of host and returns the streaming
+// response. The caller must close the response body.
+func postChat(ctx context.Context, host Host, r chatRequest, client *http.Client) (*http.Response, error) {
+	body, _ := json.Marshal(r.payload())
+
+	req, err := http.NewRequestWithContext(ctx, "POST", host.URL+"/api/chat", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
 		defer resp.Body.Close()
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("API returned non-200 status: %s. Body: %s", resp.Status, string(bodyBytes))
+	}
+	return resp, nil
+}
 
-		if resp.StatusCode != http.StatusOK {
-			bodyBytes, _ := io.ReadAll(resp.Body)
-			return chatReadyErr(fmt.Errorf("API returned non-200 status: %s. Body: %s", resp.Status, string(bodyBytes)))
+// decodeChatStream reads newline-delimited chat chunks from r, calling onChunk
+// for each one, until the final chunk arrives or the stream ends. It returns
+// the final chunk, which carries the response metadata.
+func decodeChatStream(r io.Reader, onChunk func(streamChunk)) (streamChunk, error) {
+	decoder := json.NewDecoder(r)
+	for {
+		var chunk streamChunk
+		if err := decoder.Decode(&chunk); err != nil {
+			if err != io.EOF {
+				return streamChunk{}, err
+			}
+			return streamChunk{}, nil
 		}
+		onChunk(chunk)
+		if chunk.Done {
+			return chunk, nil
+		}
+	}
+}
 
-		return chatReadyMsg{}
+// metaFromChunk converts the final chunk of a stream into LLMResponseMeta.
+func metaFromChunk(chunk streamChunk) LLMResponseMeta {
+	return LLMResponseMeta{
+		Model:              chunk.Model,
+		CreatedAt:          time.Now(),
+		Done:               chunk.Done,
+		TotalDuration:      chunk.TotalDuration,
+		LoadDuration:       chunk.LoadDuration,
+		PromptEvalCount:    chunk.PromptEvalCount,
+		PromptEvalDuration: chunk.PromptEvalDuration,
+		EvalCount:          chunk.EvalCount,
+		EvalDuration:       chunk.EvalDuration,
 	}
 }
 
 // streamChatCmd is a Bubble Tea command that initiates a streaming chat conversation
 // with the selected language model. It sends the chat history and streams back
 // responses chunk by chunk.
-// It sends streamChunkMsg for each new chunk and streamEndMsg when the stream completes.
+// It sends streamChunkMsg for each new chunk of the answer, streamThinkingMsg for
+// reasoning reported in the thinking field, and streamEndMsg when the stream completes.
 // Errors during streaming result in a streamErr message.
-func streamChatCmd(p *tea.Program, host Host, modelName string, history []chatMessage, systemPrompt string, JSONFormat bool, parameters Parameters, client *http.Client) tea.Cmd {
+func streamChatCmd(p *tea.Program, host Host, r chatRequest, client *http.Client) tea.Cmd {
 	return func() tea.Msg {
-		messages := history
-		if systemPrompt != "" {
-			messages = append([]chatMessage{{Role: "system", Content: systemPrompt}}, messages...)
-		}
-
-		payload := map[string]any{
-			"model":    modelName,
-			"messages": messages,
-			"options":  parameters,
-			"stream":   true,
-		}
-
-		if JSONFormat {
-			payload = map[string]any{
-				"model":    modelName,
-				"messages": messages,
-				"options":  parameters,
-				"stream":   true,
-				"format":   "json",
-			}
-		}
-
-		body, _ := json.Marshal(payload)
-
-		req, err := http.NewRequestWithContext(context.Background(), "POST", host.URL+"/api/chat", bytes.NewReader(body))
-		if err != nil {
-			return streamErr(err)
-		}
-		req.Header.Set("Content-Type", "application/json")
-
-		resp, err := client.Do(req)
+		resp, err := postChat(context.Background(), host, r, client)
 		if err != nil {
 			return streamErr(err)
 		}
 
 		go func() {
 			defer resp.Body.Close()
-			decoder := json.NewDecoder(resp.Body)
-			var finalChunk streamChunk
-			for {
-				var chunk streamChunk
-				if err := decoder.Decode(&chunk); err != nil {
-					if err != io.EOF {
-						p.Send(streamErr(err))
-					}
-					break
+			finalChunk, err := decodeChatStream(resp.Body, func(chunk streamChunk) {
+				if chunk.Message.Thinking != "" {
+					p.Send(streamThinkingMsg(chunk.Message.Thinking))
 				}
-				p.Send(streamChunkMsg(chunk.Message.Content))
-				if chunk.Done {
-					finalChunk = chunk
-					break
+				if len(chunk.Message.ToolCalls) > 0 {
+					p.Send(streamToolCallsMsg(chunk.Message.ToolCalls))
 				}
+				p.Send(streamChunkMsg(chunk.Message.Content))
+			})
+			if err != nil {
+				p.Send(streamErr(err))
 			}
-			p.Send(streamEndMsg{meta: LLMResponseMeta{
-				Model:              finalChunk.Model,
-				CreatedAt:          time.Now(),
-				Done:               finalChunk.Done,
-				TotalDuration:      finalChunk.TotalDuration,
-				LoadDuration:       finalChunk.LoadDuration,
-				PromptEvalCount:    finalChunk.PromptEvalCount,
-				PromptEvalDuration: finalChunk.PromptEvalDuration,
-				EvalCount:          finalChunk.EvalCount,
-				EvalDuration:       finalChunk.EvalDuration,
-			}})
+			p.Send(streamEndMsg{meta: metaFromChunk(finalChunk)})
 		}()
 
 		return nil
@@ -524,6 +723,20 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.state == viewChat && len(m.pendingToolCalls) > 0 {
+			switch msg.String() {
+			case "y", "n":
+				// Tool calls arrive before the reply ends; answering early
+				// would put the results ahead of the message requesting them.
+				if m.isLoading {
+					return m, nil
+				}
+				return m, m.resolveToolCalls(msg.String() == "y")
+			case "ctrl+c":
+				return m, tea.Quit
+			}
+			return m, nil
+		}
 		switch msg.String() {
 		case "ctrl+c", "q":
 			return m, tea.Quit
@@ -532,6 +745,16 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.state = viewHostSelector
 				return m, nil
 			}
+		case "ctrl+t":
+			if m.state == viewChat {
+				m.showThinking = !m.showThinking
+				return m, nil
+			}
+		case "esc":
+			if m.state == viewChat && m.editDepth >= 0 {
+				m.cancelEdit()
+				return m, nil
+			}
 		}
 
 	case tea.WindowSizeMsg:
@@ -573,26 +796,71 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case streamChunkMsg:
-		m.responseBuf.WriteString(string(msg))
+		answer, thinking := m.splitter.feed(string(msg))
+		if m.responseBuf.Len() == 0 {
+			answer = strings.TrimLeft(answer, "\n")
+		}
+		m.responseBuf.WriteString(answer)
+		m.thinkingBuf.WriteString(thinking)
 		m.viewport.GotoBottom()
 		return m, nil
 
+	case streamThinkingMsg:
+		m.thinkingBuf.WriteString(string(msg))
+		m.viewport.GotoBottom()
+		return m, nil
+
+	case streamToolCallsMsg:
+		m.pendingToolCalls = append(m.pendingToolCalls, msg...)
+		return m, nil
+
 	case streamEndMsg:
 		m.responseMeta = msg.meta
-		if m.responseBuf.Len() > 0 {
-			m.chatHistory = append(m.chatHistory, chatMessage{
-				Role:    "assistant",
-				Content: m.responseBuf.String(),
-			})
-			m.responseBuf.Reset()
+		if msg.meta.Done {
+			m.contextUsed = msg.meta.PromptEvalCount + msg.meta.EvalCount
+			m.sessionPromptTokens += msg.meta.PromptEvalCount
+		}
+		answer, thinking := m.splitter.flush()
+		m.responseBuf.WriteString(answer)
+		m.thinkingBuf.WriteString(thinking)
+		if m.responseBuf.Len() > 0 || m.thinkingBuf.Len() > 0 || len(m.pendingToolCalls) > 0 {
+			reply := chatMessage{
+				Role:      "assistant",
+				Content:   m.responseBuf.String(),
+				ToolCalls: m.pendingToolCalls,
+				thinking:  m.thinkingBuf.String(),
+			}
+			if m.forkDepth >= 0 {
+				m.session.fork(m.forkDepth, reply)
+			} else {
+				m.session.append(reply)
+			}
+		}
+		if schema := m.activeSchema(); schema != nil && m.responseBuf.Len() > 0 {
+			m.schemaCheck = checkResponse(schema, m.responseBuf.String())
 		}
+		m.responseBuf.Reset()
+		m.thinkingBuf.Reset()
+		m.forkDepth = -1
+		m.chatHistory = m.session.path()
 		m.isLoading = false
 		m.textArea.Focus()
 		m.viewport.GotoBottom()
 		return m, nil
 
+	case historySummaryMsg:
+		m.contextSummary = msg.summary
+		m.summaryCovers = msg.covers
+		return m, m.streamChat()
+
 	case streamErr:
 		m.isLoading = false
+		m.pendingToolCalls = nil
+		m.responseBuf.Reset()
+		m.thinkingBuf.Reset()
+		m.splitter = thinkSplitter{}
+		m.forkDepth = -1
+		m.chatHistory = m.session.path()
 		m.err = msg
 		return m, nil
 	case tickMsg:
@@ -637,16 +905,24 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		cmds = append(cmds, cmd)
 
 		if msg, ok := msg.(tea.KeyMsg); ok && msg.String() == "enter" {
-			userInput := strings.TrimSpace(m.textArea.Value())
-			if userInput != "" {
-				m.responseMeta = LLMResponseMeta{}
-				m.requestStartTime = time.Now()
-				m.chatHistory = append(m.chatHistory, chatMessage{Role: "user", Content: userInput})
+			userInput, command := slashCommand(strings.TrimSpace(m.textArea.Value()))
+			if command {
 				m.textArea.Reset()
-				m.isLoading = true
-				m.err = nil
-
-				cmds = append(cmds, m.spinner.Tick, streamChatCmd(m.program, m.selectedHost, m.selectedModel, m.chatHistory, m.selectedHost.SystemPrompt, m.config.JSON, m.selectedHost.Parameters, m.client))
+				cmds = append(cmds, m.runSlashCommand(userInput))
+			} else if userInput != "" {
+				userMsg := attachToMessage(chatMessage{Role: "user", Content: userInput}, m.attachments)
+				m.attachments = nil
+				if m.editDepth >= 0 {
+					m.session.fork(m.editDepth, userMsg)
+					m.invalidateSummary(m.editDepth)
+					m.editDepth = -1
+					m.editImages = 0
+				} else {
+					m.session.append(userMsg)
+				}
+				m.chatHistory = m.session.path()
+				m.notice = ""
+				cmds = append(cmds, m.sendChat())
 			}
 		}
 	}
@@ -659,6 +935,84 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
+// sendChat starts streaming a reply to the current chat history and puts the
+// UI into its loading state.
+func (m *model) sendChat() tea.Cmd {
+	m.responseMeta = LLMResponseMeta{}
+	m.schemaCheck = schemaResult{}
+	m.requestStartTime = time.Now()
+	m.textArea.Reset()
+	m.isLoading = true
+	m.err = nil
+
+	policy := newContextPolicy(m.config, m.selectedHost.Parameters)
+	if policy.strategy == "" && estimateTokens(m.chatHistory) >= int(float64(policy.window)*contextWarnRatio) {
+		m.notice = fmt.Sprintf("History is close to the %d token context window; set context_strategy to trim it.", policy.window)
+	}
+
+	return tea.Batch(m.spinner.Tick, m.streamChat())
+}
+
+// streamChat applies the context strategy to the chat history and starts the
+// request. When the summarize strategy needs a fresh summary, it requests
+// that first; the stream starts once historySummaryMsg arrives.
+func (m *model) streamChat() tea.Cmd {
+	policy := newContextPolicy(m.config, m.selectedHost.Parameters)
+	history, needSummary := policy.fit(m.chatHistory, m.selectedHost.SystemPrompt, m.contextSummary, m.summaryCovers)
+	if needSummary {
+		split := policy.splitIndex(m.chatHistory)
+		start, previous := m.summaryCovers, m.contextSummary
+		if start > split {
+			start, previous = 0, ""
+		}
+		return summarizeHistoryCmd(m.selectedHost, m.selectedModel, previous, m.chatHistory[start:split], split, m.client)
+	}
+	r := newChatRequest(m.config, m.selectedHost, m.selectedModel, history)
+	r.think = m.think
+	r.schema = m.activeSchema()
+	if m.config.Tools {
+		r.tools = toolSchemas(builtinTools)
+	}
+	return streamChatCmd(m.program, m.selectedHost, r, m.client)
+}
+
+// activeSchema returns the JSON Schema responses must follow: the session
+// schema if one was set with /schema, otherwise the host's schema.
+func (m *model) activeSchema() map[string]any {
+	if m.sessionSchema != nil {
+		return m.sessionSchema
+	}
+	return m.selectedHost.schema
+}
+
+// resolveToolCalls runs the pending tool calls when approved, or reports them
+// as declined, then sends the results back to the model so it can continue.
+func (m *model) resolveToolCalls(approved bool) tea.Cmd {
+	for _, call := range m.pendingToolCalls {
+		result := chatMessage{Role: "tool", ToolName: call.Function.Name, Content: "The user declined to run this tool."}
+		if approved {
+			result = runToolCall(call)
+		}
+		m.session.append(result)
+	}
+	m.pendingToolCalls = nil
+	m.chatHistory = m.session.path()
+	m.notice = ""
+	return m.sendChat()
+}
+
+// invalidateSummary discards the history summary when the message at depth,
+// which it covers, has been replaced by another branch. The measured context
+// usage belongs to the old path too, so the header estimates it until the
+// next reply.
+func (m *model) invalidateSummary(depth int) {
+	m.contextUsed = 0
+	if depth < m.summaryCovers {
+		m.contextSummary = ""
+		m.summaryCovers = 0
+	}
+}
+
 // View renders the application's UI based on its current state.
 func (m *model) View() string {
 	if m.width == 0 {
@@ -829,12 +1183,23 @@ func (m *model) chatView() string {
 	jsonModeStyle := lipgloss.NewStyle().Background(lipgloss.Color("255")).Foreground(lipgloss.Color("0")).Padding(0, 1).MarginLeft(1)
 	paramStyle := lipgloss.NewStyle().Background(lipgloss.Color("0")).Foreground(lipgloss.Color("40")).Padding(0, 1).MarginLeft(1).MarginTop(1).Width(longestLength + 2)
 
+	contextUsed, estimated := m.contextUsed, false
+	if contextUsed == 0 {
+		contextUsed, estimated = estimateTokens(m.chatHistory), true
+	}
+
 	status := lipgloss.JoinHorizontal(lipgloss.Top,
 		labelStyle.Render("Config:"),
 		headerStyle.Render(hostInfo),
 		headerStyle.MarginLeft(1).Render(modelInfo),
 		jsonModeStyle.Render(JSONMode),
+		jsonModeStyle.Render(thinkLabel(m.think)),
+		contextGauge(contextUsed, contextWindow(m.selectedHost.Parameters), estimated),
+		sessionUsage(m.sessionPromptTokens),
 	)
+	if m.activeSchema() != nil {
+		status = lipgloss.JoinHorizontal(lipgloss.Top, status, lipgloss.NewStyle().MarginLeft(1).Render(m.schemaCheck.label()))
+	}
 
 	configSettingsLine1 := lipgloss.JoinHorizontal(lipgloss.Top,
 		paramStyle.MarginLeft(len(labelString)+1).Render(modelTopK),
@@ -858,29 +1223,54 @@ func (m *model) chatView() string {
 		paramStyle.MarginLeft(len(labelString)+1).Render(modelFrequencyPenalty),
 	)
 
-	help := lipgloss.NewStyle().Render(" (tab to change, esc to quit)")
+	help := lipgloss.NewStyle().Render(" (tab to change, esc to quit, /retry /edit [n|cancel] /prev /next /attach <path> /think /schema, ctrl+t thoughts)")
 	builder.WriteString(status + help + configSettingsLine1 + configSettingsLine2 + configSettingsLine3 + configSettingsLine4 + "\n\n")
 
 	var historyBuilder strings.Builder
 	userStyle := lipgloss.NewStyle().Bold(true)
 	assistantStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("5"))
+	toolStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("244"))
 
-	for _, msg := range m.chatHistory {
+	for i, msg := range m.chatHistory {
 		var role, content string
-		if msg.Role == "assistant" {
-			role = assistantStyle.Render("Assistant: ")
+		var variant string
+		if index, count := m.session.siblings(i); count > 1 {
+			variant = fmt.Sprintf(" (%d/%d)", index+1, count)
+		}
+		switch msg.Role {
+		case "assistant":
+			role = assistantStyle.Render("Assistant" + variant + ": ")
 			content = msg.Content
-		} else {
-			role = userStyle.Render("You: ")
+			if m.config.JSON || m.activeSchema() != nil {
+				content = prettyJSON(content)
+			}
+			if section := renderThinking(msg.thinking, m.showThinking, m.width-lipgloss.Width(role)-2); section != "" {
+				content = section + "\n" + content
+			}
+			for _, call := range msg.ToolCalls {
+				content += "\n" + toolStyle.Render("→ "+call.String())
+			}
+		case "tool":
+			role = toolStyle.Render("Tool (" + msg.ToolName + "): ")
+			content = msg.Content
+		default:
+			role = userStyle.Render("You" + variant + ": ")
 			content = msg.Content
 		}
+		if len(msg.attachments) > 0 {
+			content += "\n[attached: " + strings.Join(msg.attachments, ", ") + "]"
+		}
 		wrappedContent := lipgloss.NewStyle().Width(m.width - lipgloss.Width(role) - 2).Render(content)
 		historyBuilder.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, role, wrappedContent) + "\n")
 	}
 
-	if m.responseBuf.Len() > 0 {
+	if m.responseBuf.Len() > 0 || m.thinkingBuf.Len() > 0 {
 		role := assistantStyle.Render("Assistant: ")
-		wrappedContent := lipgloss.NewStyle().Width(m.width - lipgloss.Width(role) - 2).Render(m.responseBuf.String())
+		content := m.responseBuf.String()
+		if section := renderThinking(m.thinkingBuf.String(), m.showThinking, m.width-lipgloss.Width(role)-2); section != "" {
+			content = section + "\n" + content
+		}
+		wrappedContent := lipgloss.NewStyle().Width(m.width - lipgloss.Width(role) - 2).Render(content)
 		historyBuilder.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, role, wrappedContent))
 	}
 
@@ -891,7 +1281,20 @@ func (m *model) chatView() string {
 		timer := fmt.Sprintf("%.1f", time.Since(m.requestStartTime).Seconds())
 		loadingText := fmt.Sprintf(" Assistant is thinking... %ss", timer)
 		builder.WriteString("\n" + m.spinner.View() + loadingText)
+	} else if len(m.pendingToolCalls) > 0 {
+		var calls []string
+		for _, call := range m.pendingToolCalls {
+			calls = append(calls, call.String())
+		}
+		confirmStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("214"))
+		builder.WriteString("\n" + confirmStyle.Render("The model wants to run: "+strings.Join(calls, ", ")+"  Allow? (y/n)"))
 	} else {
+		if m.notice != "" {
+			builder.WriteString("\n" + lipgloss.NewStyle().Faint(true).Render(m.notice))
+		}
+		if len(m.attachments) > 0 {
+			builder.WriteString("\n" + lipgloss.NewStyle().Faint(true).Render("Attached: "+strings.Join(attachmentNames(m.attachments), ", ")))
+		}
 		builder.WriteString("\n" + m.textArea.View())
 	}
 
@@ -925,9 +1328,10 @@ func formatMeta(meta LLMResponseMeta) string {
 
 // StartGUI initializes and runs the interactive TUI for single-model chat.
 // It reads configuration from config.json, optionally switches to multimodel
-// mode, and blocks until the UI exits. It logs diagnostic output to debug.log
-// when enabled. StartGUI does not return a value.
-func StartGUI(configPath string) {
+// mode, which a non-empty preset also selects, and blocks until the UI exits.
+// It logs diagnostic output to debug.log when enabled. StartGUI does not
+// return a value.
+func StartGUI(configPath, preset string) {
 	f, err := tea.LogToFile("debug.log", "debug")
 	if err != nil {
 		log.Fatalf("could not open log file: %v", err)
@@ -939,9 +1343,14 @@ func StartGUI(configPath string) {
 		log.Fatalf("Failed to start: %v", err)
 	}
 
-	if cfg.Multimodel {
-		models.UnloadModels()
-		if err := StartMultimodelGUI(cfg); err != nil {
+	if cfg.Multimodel || preset != "" {
+		if _, ok := cfg.Presets[preset]; preset != "" && !ok {
+			log.Fatalf("Failed to start: preset %q not found in config", preset)
+		}
+		if cfg.UnloadOnStart {
+			models.UnloadModels()
+		}
+		if err := StartMultimodelGUI(cfg, preset); err != nil {
 			log.Fatalf("Error running multimodel program: %v", err)
 		}
 		return
@@ -4,9 +4,7 @@ package cli
 import (
 	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"strings"
 	"time"
@@ -14,11 +12,16 @@ import (
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
+// minColumnWidth is the narrowest a multimodel column is drawn before the
+// columns are paged horizontally.
+const minColumnWidth = 30
+
 // multimodelViewState represents the current state of the multimodel application's view.
 type multimodelViewState int
 
@@ -29,25 +32,51 @@ const (
 	multimodelViewLoadingChat
 	// multimodelViewChat is the multimodel chat interface
 	multimodelViewChat
+	// multimodelViewPresets is the preset picker shown when presets are configured
+	multimodelViewPresets
 )
 
-// hostModelAssignment represents a host with its selected model assignment.
+// hostModelAssignment is a lane: a host with its selected model. Several lanes
+// may target the same host, each with its own copy of the host settings so the
+// system prompt and parameters can differ per lane.
 type hostModelAssignment struct {
 	host          Host
 	selectedModel string
 	models        []string
 	isAssigned    bool
+	loadState     laneLoadState // Whether the lane's model has been preloaded
+	loadTime      time.Duration // How long the last preload took
+	loadErr       error         // Why the last preload failed
 }
 
+// laneLoadState is the preload status of a lane's model.
+type laneLoadState int
+
+const (
+	// laneNotLoaded means the model has not been preloaded since it was assigned
+	laneNotLoaded laneLoadState = iota
+	// laneLoading means the model is being preloaded
+	laneLoading
+	// laneLoaded means the model answered the preload request
+	laneLoaded
+	// laneLoadFailed means the preload request failed
+	laneLoadFailed
+)
+
 // multimodelColumnResponse holds streaming state and metadata for a single column.
 type multimodelColumnResponse struct {
-	hostIndex        int
-	content          strings.Builder
+	hostIndex        int // Index of the lane this column shows
+	content          bytes.Buffer
 	isStreaming      bool
 	error            error
 	meta             LLMResponseMeta
 	chatHistory      []chatMessage // Add chat history for this column
 	requestStartTime time.Time
+	splitter         thinkSplitter  // Separates inline <think> blocks from the answer
+	schemaCheck      schemaResult   // Validation of the last response against the lane schema
+	viewport         viewport.Model // Scrollable view of the column's chat history
+	muted            bool           // Skipped by messages sent to all columns
+	turn             int            // Turn of the last prompt sent to this column
 }
 
 // multimodelModel is the Bubble Tea model for multimodel mode.
@@ -71,16 +100,70 @@ type multimodelModel struct {
 	modelList list.Model
 	// Indicates if we're in model selection mode
 	inModelSelection bool
+	// Lane setting being edited, if any
+	laneEdit laneEditField
+	// Input for editing lane settings
+	laneInput textinput.Model
+	// Whether lanes on the same host are sent one after another
+	serializePerHost bool
 
 	// Chat interface components
 	textArea textarea.Model
-	viewport viewport.Model
 	spinner  spinner.Model
 
+	// Feedback from the last chat command, shown above the input
+	notice string
+	// Files queued with /attach for the next message
+	attachments []attachment
+	// Reasoning setting sent with each request; nil uses the model default
+	think *bool
+	// Whether reasoning sections are expanded in the columns
+	showThinking bool
+	// Schema set with /schema for this session; it overrides host schemas
+	sessionSchema map[string]any
+
 	// Chat data
+	// Prompts and the combined answers of every round, sent to the lanes in
+	// shared history mode
 	chatHistory      []chatMessage
-	columnResponses  [4]multimodelColumnResponse
+	columnResponses  []multimodelColumnResponse
 	requestStartTime time.Time
+	// Index of the first assigned column shown when not all of them fit
+	columnOffset int
+	// Lane index of the column that receives scroll keys
+	focusedColumn int
+	// Whether the focused column is expanded to the full width
+	zoomed bool
+	// Columns compared with /diff; nil when diff mode is off
+	diff *diffState
+	// Scrollable view of the diff
+	diffViewport viewport.Model
+	// Pairwise similarity of the latest responses
+	similarities []similarity
+	// Whether a round is being scored by the judge
+	judging bool
+	// Numbers judge requests, so only the answer to the latest one counts
+	judgeSeq int
+	// Changes whenever lanes are added or removed and their indices shift
+	laneLayout int
+	// Judge scores of the last judged round, best first
+	judgement []judgeScore
+	// Judge results per lane over the session, keyed by lane label
+	scoreboard map[string]*scoreboardEntry
+	// Whether the scoreboard is shown instead of the last judgement
+	showScoreboard bool
+	// Identifies this session in the votes file
+	sessionID string
+	// Number of prompts sent in this session
+	turn int
+	// Ranks voted for the current turn, by lane index
+	votes map[int]int
+	// Lanes the current round was sent to; nil means all assigned lanes
+	targets map[int]bool
+	// Whether lanes are sent the shared history instead of their own
+	sharedHistory bool
+	// Selected entry in the preset picker; 0 is manual assignment
+	presetIndex int
 
 	// UI dimensions
 	width, height int
@@ -117,11 +200,15 @@ func (i assignmentItem) FilterValue() string {
 // multimodelAssignmentsReadyMsg is sent when model assignments are loaded.
 type multimodelAssignmentsReadyMsg struct{}
 
-// multimodelChatReadyMsg is sent when the chat interface is ready.
+// multimodelChatReadyMsg is sent when every lane's model has been loaded.
 type multimodelChatReadyMsg struct{}
 
-// multimodelChatReadyErr is sent when chat loading fails.
-type multimodelChatReadyErr error
+// laneLoadedMsg reports the result of preloading one lane's model.
+type laneLoadedMsg struct {
+	lane    int
+	elapsed time.Duration
+	err     error
+}
 
 // multimodelStreamChunkMsg carries a streaming message update for a column.
 type multimodelStreamChunkMsg struct {
@@ -141,6 +228,14 @@ type multimodelStreamErr struct {
 	err       error
 }
 
+// newColumnResponse returns an empty column for the lane at index.
+func newColumnResponse(index int) multimodelColumnResponse {
+	return multimodelColumnResponse{
+		hostIndex: index,
+		viewport:  viewport.New(0, 0),
+	}
+}
+
 // initialMultimodelModel creates a new multimodel Bubble Tea model with defaults.
 func initialMultimodelModel(cfg *Config) *multimodelModel {
 	s := spinner.New()
@@ -156,8 +251,6 @@ func initialMultimodelModel(cfg *Config) *multimodelModel {
 	ta.SetHeight(1)
 	ta.KeyMap.InsertNewline.SetEnabled(false)
 
-	vp := viewport.New(100, 5)
-
 	assignments := make([]hostModelAssignment, len(cfg.Hosts))
 	for i, host := range cfg.Hosts {
 		assignments[i] = hostModelAssignment{
@@ -167,132 +260,241 @@ func initialMultimodelModel(cfg *Config) *multimodelModel {
 		}
 	}
 
-	var columnResponses [4]multimodelColumnResponse
+	columnResponses := make([]multimodelColumnResponse, len(assignments))
 	for i := range columnResponses {
-		columnResponses[i] = multimodelColumnResponse{
-			hostIndex: i,
-		}
+		columnResponses[i] = newColumnResponse(i)
 	}
 
 	modelList := list.New(nil, list.NewDefaultDelegate(), 0, 0)
 
+	laneInput := textinput.New()
+	laneInput.CharLimit = -1
+
+	state := multimodelViewAssignment
+	if len(cfg.Presets) > 0 {
+		state = multimodelViewPresets
+	}
+
 	return &multimodelModel{
 		config:            cfg,
-		state:             multimodelViewAssignment,
+		state:             state,
 		assignments:       assignments,
 		selectedHostIndex: 0,
 		modelList:         modelList,
 		spinner:           s,
 		textArea:          ta,
-		viewport:          vp,
 		columnResponses:   columnResponses,
+		think:             cfg.Think,
+		laneInput:         laneInput,
+		diffViewport:      viewport.New(0, 0),
+		sessionID:         newSessionID(),
+		serializePerHost:  cfg.SerializePerHost,
 	}
 }
 
-// loadMultimodelChatCmd prepares the chat interface for multimodel mode.
-func loadMultimodelChatCmd(assignments []hostModelAssignment) tea.Cmd {
+// preloadLanesCmd loads the model of every assigned lane. Lanes are loaded
+// concurrently, except that lanes sharing a host are loaded one after another
+// when serializePerHost is set. Each lane reports a laneLoadedMsg.
+func (m *multimodelModel) preloadLanesCmd() tea.Cmd {
+	var cmds []tea.Cmd
+	for _, group := range m.groupLanes(m.assignedColumns()) {
+		sequence := make([]tea.Cmd, len(group))
+		for j, i := range group {
+			sequence[j] = preloadLaneCmd(i, m.assignments[i].host, m.assignments[i].selectedModel, m.client)
+		}
+		cmds = append(cmds, tea.Sequence(sequence...))
+	}
+	return tea.Batch(cmds...)
+}
+
+// preloadLaneCmd loads a lane's model and reports how long it took.
+func preloadLaneCmd(lane int, host Host, model string, client *http.Client) tea.Cmd {
 	return func() tea.Msg {
-		time.Sleep(time.Millisecond * 500) // Simulate loading
-		return multimodelChatReadyMsg{}
+		start := time.Now()
+		err := preloadModel(host, model, client)
+		return laneLoadedMsg{lane: lane, elapsed: time.Since(start), err: err}
 	}
 }
 
-// multimodelStreamChatCmd initiates streaming chat for all assigned host/model pairs.
+// multimodelStreamChatCmd initiates streaming chat for all assigned lanes.
+// Lanes in the same group, see laneGroups, are streamed one after another.
 func multimodelStreamChatCmd(p *tea.Program, m *multimodelModel) tea.Cmd {
 	return func() tea.Msg {
-		for i, assignment := range m.assignments {
-			if assignment.isAssigned {
-				go func(hostIndex int, host Host, model string, history []chatMessage) {
-					if err := streamToColumn(p, hostIndex, host, model, history, host.SystemPrompt, m.config.JSON, host.Parameters, m.client); err != nil {
+		for _, group := range m.laneGroups() {
+			hosts := make([]Host, len(group))
+			requests := make([]chatRequest, len(group))
+			for j, i := range group {
+				assignment := m.assignments[i]
+				hosts[j] = assignment.host
+				requests[j] = newChatRequest(m.config, assignment.host, assignment.selectedModel, m.requestHistory(i))
+				requests[j].think = m.think
+				requests[j].schema = m.laneSchema(i)
+			}
+			go func(group []int, hosts []Host, requests []chatRequest) {
+				for j, hostIndex := range group {
+					if err := streamToColumn(p, hostIndex, hosts[j], requests[j], m.client); err != nil {
 						p.Send(multimodelStreamErr{hostIndex: hostIndex, err: err})
 					}
-				}(i, assignment.host, assignment.selectedModel, m.columnResponses[i].chatHistory)
-			}
+				}
+			}(group, hosts, requests)
 		}
 		return nil
 	}
 }
 
 // streamToColumn streams chat responses for a single assigned column.
-func streamToColumn(p *tea.Program, hostIndex int, host Host, modelName string, history []chatMessage, systemPrompt string, JSONFormat bool, parameters Parameters, client *http.Client) error {
-	messages := history
-	if systemPrompt != "" {
-		messages = append([]chatMessage{{Role: "system", Content: systemPrompt}}, messages...)
-	}
-
-	payload := map[string]any{
-		"model":    modelName,
-		"messages": messages,
-		"options":  parameters,
-		"stream":   true,
-	}
-
-	if JSONFormat {
-		payload = map[string]any{
-			"model":    modelName,
-			"messages": messages,
-			"options":  parameters,
-			"stream":   true,
-			"format":   "json",
-		}
-	}
-
-	body, _ := json.Marshal(payload)
-
-	req, err := http.NewRequestWithContext(context.Background(), "POST", host.URL+"/api/chat", bytes.NewReader(body))
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := client.Do(req)
+func streamToColumn(p *tea.Program, hostIndex int, host Host, r chatRequest, client *http.Client) error {
+	resp, err := postChat(context.Background(), host, r, client)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	decoder := json.NewDecoder(resp.Body)
-	var finalChunk streamChunk
-	for {
-		var chunk streamChunk
-		if err := decoder.Decode(&chunk); err != nil {
-			if err != io.EOF {
-				return err
-			}
-			break
-		}
+	finalChunk, err := decodeChatStream(resp.Body, func(chunk streamChunk) {
 		p.Send(multimodelStreamChunkMsg{
 			hostIndex: hostIndex,
 			message: chatMessage{
-				Role:    chunk.Message.Role,
-				Content: chunk.Message.Content,
+				Role:     chunk.Message.Role,
+				Content:  chunk.Message.Content,
+				thinking: chunk.Message.Thinking,
 			},
 		})
-		if chunk.Done {
-			finalChunk = chunk
-			break
-		}
+	})
+	if err != nil {
+		return err
 	}
 
 	p.Send(multimodelStreamEndMsg{
 		hostIndex: hostIndex,
-		meta: LLMResponseMeta{
-			Model:              finalChunk.Model,
-			CreatedAt:          time.Now(),
-			Done:               finalChunk.Done,
-			TotalDuration:      finalChunk.TotalDuration,
-			LoadDuration:       finalChunk.LoadDuration,
-			PromptEvalCount:    finalChunk.PromptEvalCount,
-			PromptEvalDuration: finalChunk.PromptEvalDuration,
-			EvalCount:          finalChunk.EvalCount,
-			EvalDuration:       finalChunk.EvalDuration,
-		},
+		meta:      metaFromChunk(finalChunk),
 	})
 	return nil
 }
 
+// laneSchema returns the JSON Schema for the column at index: the session
+// schema if one was set with /schema, otherwise the host's schema.
+func (m *multimodelModel) laneSchema(index int) map[string]any {
+	if m.sessionSchema != nil {
+		return m.sessionSchema
+	}
+	if index < len(m.assignments) {
+		return m.assignments[index].host.schema
+	}
+	return nil
+}
+
+// appendReply adds streamed answer and reasoning text to the assistant message
+// at the end of the column history, starting a new message if needed.
+func (c *multimodelColumnResponse) appendReply(role, answer, thinking string) {
+	c.content.WriteString(answer)
+	if n := len(c.chatHistory); n > 0 && c.chatHistory[n-1].Role == "assistant" {
+		if c.chatHistory[n-1].Content == "" {
+			answer = strings.TrimLeft(answer, "\n")
+		}
+		c.chatHistory[n-1].Content += answer
+		c.chatHistory[n-1].thinking += thinking
+		return
+	}
+	c.chatHistory = append(c.chatHistory, chatMessage{Role: role, Content: strings.TrimLeft(answer, "\n"), thinking: thinking})
+}
+
+// assignedColumns returns the indices of the columns that have a model assigned.
+func (m *multimodelModel) assignedColumns() []int {
+	var cols []int
+	for i, a := range m.assignments {
+		if a.isAssigned {
+			cols = append(cols, i)
+		}
+	}
+	return cols
+}
+
+// columnsPerPage returns how many of n columns fit side by side in the
+// current terminal width, keeping each at least minColumnWidth wide.
+func (m *multimodelModel) columnsPerPage(n int) int {
+	perPage := (m.width - 2) / (minColumnWidth + 2)
+	if perPage < 1 {
+		perPage = 1
+	}
+	if perPage > n {
+		perPage = n
+	}
+	return perPage
+}
+
+// pageColumns returns the indices of the assigned columns currently on screen.
+func (m *multimodelModel) pageColumns() []int {
+	cols := m.assignedColumns()
+	perPage := m.columnsPerPage(len(cols))
+	if m.columnOffset > len(cols)-perPage {
+		m.columnOffset = len(cols) - perPage
+	}
+	if m.columnOffset < 0 {
+		m.columnOffset = 0
+	}
+	return cols[m.columnOffset : m.columnOffset+perPage]
+}
+
+// scrollColumns moves the visible page of columns by delta columns. Focus
+// moves along when the focused column scrolls out of view.
+func (m *multimodelModel) scrollColumns(delta int) {
+	m.columnOffset += delta
+	page := m.pageColumns()
+	if len(page) == 0 {
+		return
+	}
+	if m.focusedColumn < page[0] {
+		m.focusedColumn = page[0]
+	} else if m.focusedColumn > page[len(page)-1] {
+		m.focusedColumn = page[len(page)-1]
+	}
+}
+
+// moveFocus focuses the assigned column delta positions away from the
+// current one and pages the columns so that it is visible.
+func (m *multimodelModel) moveFocus(delta int) {
+	cols := m.assignedColumns()
+	if len(cols) == 0 {
+		return
+	}
+	pos := 0
+	for j, i := range cols {
+		if i == m.focusedColumn {
+			pos = j
+		}
+	}
+	pos = max(0, min(len(cols)-1, pos+delta))
+	m.focusedColumn = cols[pos]
+
+	perPage := m.columnsPerPage(len(cols))
+	if pos < m.columnOffset {
+		m.columnOffset = pos
+	} else if pos >= m.columnOffset+perPage {
+		m.columnOffset = pos - perPage + 1
+	}
+}
+
+// focusedViewport returns the viewport of the focused column, falling back to
+// the first assigned column. It returns nil when no column is assigned.
+func (m *multimodelModel) focusedViewport() *viewport.Model {
+	if m.diff != nil {
+		return &m.diffViewport
+	}
+	cols := m.assignedColumns()
+	if len(cols) == 0 {
+		return nil
+	}
+	if m.focusedColumn >= len(m.assignments) || !m.assignments[m.focusedColumn].isAssigned {
+		m.focusedColumn = cols[0]
+	}
+	return &m.columnResponses[m.focusedColumn].viewport
+}
+
 // Init initializes the multimodel Bubble Tea model.
 func (m *multimodelModel) Init() tea.Cmd {
+	if m.state == multimodelViewLoadingChat {
+		return tea.Batch(m.spinner.Tick, m.preloadLanesCmd(), tickCmd())
+	}
 	return m.spinner.Tick
 }
 
@@ -313,16 +515,72 @@ func (m *multimodelModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.state = multimodelViewAssignment
 				return m, nil
 			}
+		case "ctrl+t":
+			if m.state == multimodelViewChat {
+				m.showThinking = !m.showThinking
+				return m, nil
+			}
+		case "shift+left":
+			if m.state == multimodelViewChat {
+				m.scrollColumns(-1)
+				return m, nil
+			}
+		case "shift+right":
+			if m.state == multimodelViewChat {
+				m.scrollColumns(1)
+				return m, nil
+			}
+		case "ctrl+left", "ctrl+right":
+			if m.state == multimodelViewChat {
+				delta := 1
+				if msg.String() == "ctrl+left" {
+					delta = -1
+				}
+				m.moveFocus(delta)
+				return m, nil
+			}
+		case "ctrl+o":
+			if m.state == multimodelViewChat {
+				m.zoomed = !m.zoomed
+				return m, nil
+			}
+		case "ctrl+x":
+			if m.focusedViewport() != nil && m.state == multimodelViewChat && m.diff == nil {
+				col := &m.columnResponses[m.focusedColumn]
+				col.muted = !col.muted
+				return m, nil
+			}
+		case "ctrl+b":
+			if m.focusedViewport() != nil && m.state == multimodelViewChat && m.diff == nil {
+				m.notice = m.castVote(map[int]int{m.focusedColumn: 1})
+				return m, nil
+			}
+		case "pgup", "pgdown", "ctrl+up", "ctrl+down":
+			if vp := m.focusedViewport(); vp != nil && m.state == multimodelViewChat {
+				switch msg.String() {
+				case "pgup":
+					vp.PageUp()
+				case "pgdown":
+					vp.PageDown()
+				case "ctrl+up":
+					vp.ScrollUp(1)
+				case "ctrl+down":
+					vp.ScrollDown(1)
+				}
+				return m, nil
+			}
+		}
+
+	case tea.MouseMsg:
+		if vp := m.focusedViewport(); vp != nil && m.state == multimodelViewChat {
+			*vp, cmd = vp.Update(msg)
+			return m, cmd
 		}
 
 	case tea.WindowSizeMsg:
 		m.width, m.height = msg.Width, msg.Height
 		m.modelList.SetSize(msg.Width-2, msg.Height-8)
 		m.textArea.SetWidth(msg.Width - 3)
-		headerHeight := 6
-		footerHeight := 5
-		m.viewport.Width = msg.Width
-		m.viewport.Height = msg.Height - headerHeight - footerHeight
 
 	case multimodelChatReadyMsg:
 		m.isLoading = false
@@ -330,27 +588,54 @@ func (m *multimodelModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.textArea.Focus()
 		return m, nil
 
-	case multimodelChatReadyErr:
+	case laneLoadedMsg:
+		if msg.lane < len(m.assignments) {
+			lane := &m.assignments[msg.lane]
+			lane.loadTime = msg.elapsed
+			lane.loadErr = msg.err
+			lane.loadState = laneLoaded
+			if msg.err != nil {
+				lane.loadState = laneLoadFailed
+			}
+		}
+		failed := 0
+		for _, i := range m.assignedColumns() {
+			switch m.assignments[i].loadState {
+			case laneLoading:
+				return m, nil
+			case laneLoadFailed:
+				failed++
+			}
+		}
+		if failed == 0 {
+			return m, func() tea.Msg { return multimodelChatReadyMsg{} }
+		}
+		// Return to the assignment view so the failed lanes can be fixed.
 		m.isLoading = false
-		m.err = msg
+		m.state = multimodelViewAssignment
+		m.notice = fmt.Sprintf("%d lane(s) failed to load. Reassign them or press 'C' to retry.", failed)
 		return m, nil
 
 	case multimodelStreamChunkMsg:
 		if msg.hostIndex < len(m.columnResponses) {
-			history := &m.columnResponses[msg.hostIndex].chatHistory
-			if len(*history) > 0 && (*history)[len(*history)-1].Role == "assistant" {
-				(*history)[len(*history)-1].Content += msg.message.Content
-			} else {
-				*history = append(*history, msg.message)
-			}
-			m.columnResponses[msg.hostIndex].isStreaming = true
+			col := &m.columnResponses[msg.hostIndex]
+			answer, thinking := col.splitter.feed(msg.message.Content)
+			col.appendReply(msg.message.Role, answer, msg.message.thinking+thinking)
+			col.isStreaming = true
 		}
 		return m, nil
 
 	case multimodelStreamEndMsg:
 		if msg.hostIndex < len(m.columnResponses) {
-			m.columnResponses[msg.hostIndex].meta = msg.meta
-			m.columnResponses[msg.hostIndex].isStreaming = false
+			col := &m.columnResponses[msg.hostIndex]
+			if answer, thinking := col.splitter.flush(); answer != "" || thinking != "" {
+				col.appendReply("assistant", answer, thinking)
+			}
+			col.meta = msg.meta
+			col.isStreaming = false
+			if schema := m.laneSchema(msg.hostIndex); schema != nil && col.content.Len() > 0 {
+				col.schemaCheck = checkResponse(schema, col.content.String())
+			}
 		}
 		allDone := true
 		for i, assignment := range m.assignments {
@@ -363,13 +648,15 @@ func (m *multimodelModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.isLoading = false
 			m.textArea.Focus()
 			m.textArea.Reset()
+			m.refreshComparisons()
+			m.judgement = nil
 
-			for i, assignment := range m.assignments {
-				if assignment.isAssigned && i < len(m.columnResponses) && m.columnResponses[i].content.Len() > 0 {
+			for _, i := range m.roundColumns() {
+				if m.columnResponses[i].content.Len() > 0 {
 					if len(m.chatHistory) > 0 && m.chatHistory[len(m.chatHistory)-1].Role == "user" {
 						var combinedResponse strings.Builder
-						for j, a := range m.assignments {
-							if a.isAssigned && j < len(m.columnResponses) && m.columnResponses[j].content.Len() > 0 {
+						for _, j := range m.roundColumns() {
+							if a := m.assignments[j]; m.columnResponses[j].content.Len() > 0 {
 								combinedResponse.WriteString(fmt.Sprintf("[%s - %s]: %s\n\n",
 									a.host.Name, a.selectedModel, m.columnResponses[j].content.String()))
 							}
@@ -384,11 +671,33 @@ func (m *multimodelModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					}
 				}
 			}
+
+			if m.config.Judge != nil && m.config.Judge.Auto && len(m.roundColumns()) > 1 {
+				return m, m.startJudging()
+			}
+		}
+		return m, nil
+
+	case judgeResultMsg:
+		if msg.seq != m.judgeSeq {
+			// A newer request replaced this one.
+			return m, nil
+		}
+		m.judging = false
+		if msg.turn != m.turn || msg.layout != m.laneLayout {
+			m.notice = "Dropped the verdict on an earlier round."
+			return m, nil
+		}
+		if msg.err != nil {
+			m.notice = fmt.Sprintf("Judge failed: %v", msg.err)
+		} else {
+			m.recordJudgement(msg.scores)
 		}
 		return m, nil
 
 	case multimodelStreamErr:
 		if msg.hostIndex < len(m.columnResponses) {
+			m.columnResponses[msg.hostIndex].splitter = thinkSplitter{}
 			m.columnResponses[msg.hostIndex].error = msg.err
 			m.columnResponses[msg.hostIndex].isStreaming = false
 		}
@@ -402,6 +711,8 @@ func (m *multimodelModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	}
 
 	switch m.state {
+	case multimodelViewPresets:
+		return m.updatePresets(msg)
 	case multimodelViewAssignment:
 		return m.updateAssignment(msg)
 	case multimodelViewChat:
@@ -431,6 +742,7 @@ func (m *multimodelModel) updateAssignment(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if selectedItem, ok := m.modelList.SelectedItem().(item); ok {
 					m.assignments[m.selectedHostIndex].selectedModel = selectedItem.Title()
 					m.assignments[m.selectedHostIndex].isAssigned = true
+					m.assignments[m.selectedHostIndex].loadState = laneNotLoaded
 					m.inModelSelection = false
 				}
 			case "esc":
@@ -438,9 +750,48 @@ func (m *multimodelModel) updateAssignment(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, nil
 			}
 		}
+	} else if m.laneEdit != laneEditNone {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			switch keyMsg.String() {
+			case "enter":
+				m.applyLaneEdit()
+				return m, nil
+			case "esc":
+				m.laneEdit = laneEditNone
+				m.notice = ""
+				m.laneInput.Blur()
+				return m, nil
+			}
+		}
+		var cmd tea.Cmd
+		m.laneInput, cmd = m.laneInput.Update(msg)
+		cmds = append(cmds, cmd)
 	} else {
 		if keyMsg, ok := msg.(tea.KeyMsg); ok {
 			switch keyMsg.String() {
+			case "a":
+				if m.lanesBusy() {
+					break
+				}
+				m.addLane(m.selectedHostIndex)
+				m.selectedHostIndex++
+			case "d":
+				if m.lanesBusy() {
+					break
+				}
+				if !m.deleteLane(m.selectedHostIndex) {
+					m.notice = "Each host keeps at least one lane."
+				} else {
+					m.notice = ""
+				}
+			case "s":
+				m.startLaneEdit(laneEditSystemPrompt)
+			case "p":
+				m.startLaneEdit(laneEditParameters)
+			case "x":
+				m.serializePerHost = !m.serializePerHost
+			case "w":
+				m.startLaneEdit(laneEditPresetName)
 			case "up", "k":
 				if m.selectedHostIndex > 0 {
 					m.selectedHostIndex--
@@ -459,26 +810,7 @@ func (m *multimodelModel) updateAssignment(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.modelList.Title = fmt.Sprintf("Select Model for %s", m.assignments[m.selectedHostIndex].host.Name)
 				m.inModelSelection = true
 			case "c":
-				// Start chat if at least one assignment is made
-				hasAssignment := false
-				for _, assignment := range m.assignments {
-					if assignment.isAssigned {
-						hasAssignment = true
-						break
-					}
-				}
-				if hasAssignment {
-					m.state = multimodelViewLoadingChat
-					m.isLoading = true
-					m.requestStartTime = time.Now()
-					// Clear previous responses
-					for i := range m.columnResponses {
-						m.columnResponses[i].content.Reset()
-						m.columnResponses[i].error = nil
-						m.columnResponses[i].isStreaming = false
-					}
-					cmds = append(cmds, m.spinner.Tick, loadMultimodelChatCmd(m.assignments), tickCmd())
-				}
+				cmds = append(cmds, m.startChat())
 			}
 		}
 	}
@@ -486,35 +818,72 @@ func (m *multimodelModel) updateAssignment(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
+// startChat switches to the chat once at least one lane has a model
+// assigned. It returns nil when no lane is assigned.
+func (m *multimodelModel) startChat() tea.Cmd {
+	if len(m.assignedColumns()) == 0 {
+		return nil
+	}
+	m.state = multimodelViewLoadingChat
+	m.isLoading = true
+	m.notice = ""
+	m.requestStartTime = time.Now()
+	// Clear previous responses
+	for i := range m.columnResponses {
+		m.columnResponses[i].content.Reset()
+		m.columnResponses[i].error = nil
+		m.columnResponses[i].isStreaming = false
+	}
+	for _, i := range m.assignedColumns() {
+		m.assignments[i].loadState = laneLoading
+		m.assignments[i].loadErr = nil
+	}
+	return tea.Batch(m.spinner.Tick, m.preloadLanesCmd(), tickCmd())
+}
+
 // updateChat handles updates in chat mode
 func (m *multimodelModel) updateChat(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 	var cmd tea.Cmd
 
-	m.viewport, cmd = m.viewport.Update(msg)
-	cmds = append(cmds, cmd)
-
 	m.textArea, cmd = m.textArea.Update(msg)
 	cmds = append(cmds, cmd)
 
 	if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.String() == "enter" {
-		userInput := strings.TrimSpace(m.textArea.Value())
-		if userInput != "" {
-			// Add user message to all assigned column histories
-			userMsg := chatMessage{Role: "user", Content: userInput}
+		userInput, command := slashCommand(strings.TrimSpace(m.textArea.Value()))
+		if command {
+			m.textArea.Reset()
+			cmds = append(cmds, m.runMultimodelCommand(userInput))
+		} else if userInput != "" {
+			targets, text, notice := m.parseTargets(userInput)
+			if notice != "" {
+				m.notice = notice
+				return m, tea.Batch(cmds...)
+			}
+			m.targets = targets
+
+			// Add user message to the histories of the targeted columns
+			userMsg := attachToMessage(chatMessage{Role: "user", Content: text}, m.attachments)
+			m.attachments = nil
+			m.notice = ""
+			m.chatHistory = append(m.chatHistory, userMsg)
+			m.turn++
 			for i := range m.columnResponses {
-				if m.assignments[i].isAssigned {
-					m.columnResponses[i].chatHistory = append(m.columnResponses[i].chatHistory, userMsg)
-					m.columnResponses[i].requestStartTime = time.Now()
-					m.columnResponses[i].isStreaming = true
-				} else {
+				if !m.targets[i] {
 					m.columnResponses[i].isStreaming = false
+					continue
 				}
+				m.columnResponses[i].chatHistory = append(m.columnResponses[i].chatHistory, userMsg)
+				m.columnResponses[i].turn = m.turn
+				m.columnResponses[i].requestStartTime = time.Now()
+				m.columnResponses[i].isStreaming = true
 				m.columnResponses[i].content.Reset() // Clear content buffer for new streaming response
 				m.columnResponses[i].error = nil
+				m.columnResponses[i].schemaCheck = schemaResult{}
 			}
 
 			m.requestStartTime = time.Now()
+			m.votes = nil
 			m.textArea.Blur()
 			m.isLoading = true
 			cmds = append(cmds, m.spinner.Tick, multimodelStreamChatCmd(m.program, m), tickCmd()) // Pass the entire model
@@ -536,11 +905,18 @@ func (m *multimodelModel) View() string {
 	}
 
 	switch m.state {
+	case multimodelViewPresets:
+		return m.presetView()
 	case multimodelViewAssignment:
 		return m.assignmentView()
 	case multimodelViewLoadingChat:
 		timer := fmt.Sprintf("%.1f", time.Since(m.requestStartTime).Seconds())
-		return fmt.Sprintf("\n  %s Loading multimodel chat... %ss\n", m.spinner.View(), timer)
+		var builder strings.Builder
+		builder.WriteString(fmt.Sprintf("\n  %s Loading models... %ss\n\n", m.spinner.View(), timer))
+		for _, i := range m.assignedColumns() {
+			builder.WriteString(fmt.Sprintf("  %s → %s  %s\n", m.laneName(i), m.assignments[i].selectedModel, m.loadStatus(i)))
+		}
+		return builder.String()
 	case multimodelViewChat:
 		return m.multimodelChatView()
 	default:
@@ -570,7 +946,7 @@ func (m *multimodelModel) assignmentView() string {
 		}
 
 		hostStyle := lipgloss.NewStyle().Bold(true)
-		line.WriteString(hostStyle.Render(assignment.host.Name))
+		line.WriteString(hostStyle.Render(m.laneName(i)))
 		line.WriteString(" → ")
 
 		if assignment.isAssigned {
@@ -581,13 +957,40 @@ func (m *multimodelModel) assignmentView() string {
 			line.WriteString(placeholderStyle.Render("(no model assigned)"))
 		}
 
+		var settings []string
+		if assignment.host.SystemPrompt != "" {
+			settings = append(settings, "system: "+truncate(assignment.host.SystemPrompt, 40))
+		}
+		if params := formatParams(assignment.host.Parameters); params != "" {
+			settings = append(settings, params)
+		}
+		if len(settings) > 0 {
+			line.WriteString(lipgloss.NewStyle().Faint(true).Render("  [" + strings.Join(settings, " | ") + "]"))
+		}
+		if status := m.loadStatus(i); assignment.isAssigned && status != "" {
+			line.WriteString("  " + status)
+		}
+
 		builder.WriteString(line.String() + "\n")
 	}
 
 	builder.WriteString("\n")
 
+	if m.laneEdit != laneEditNone {
+		builder.WriteString(m.laneInput.View() + "\n")
+		builder.WriteString(lipgloss.NewStyle().Faint(true).Render("Enter: Save  esc: Cancel") + "\n")
+	}
+	if m.notice != "" {
+		builder.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("9")).Render(m.notice) + "\n")
+	}
+
+	mode := "concurrently"
+	if m.serializePerHost {
+		mode = "one at a time per host"
+	}
 	helpStyle := lipgloss.NewStyle().Faint(true)
 	builder.WriteString(helpStyle.Render("↑/↓: Navigate  Enter: Select Model  C: Start Chat  esc: Quit\n"))
+	builder.WriteString(helpStyle.Render("A: Add Lane  D: Delete Lane  S: System Prompt  P: Parameters  X: Toggle Serialize (lanes run " + mode + ")  W: Save Preset\n"))
 
 	hasAssignment := false
 	for _, assignment := range m.assignments {
@@ -605,53 +1008,104 @@ func (m *multimodelModel) assignmentView() string {
 	return lipgloss.NewStyle().Margin(1, 2).Render(builder.String())
 }
 
-// multimodelChatView renders the 4-column chat interface
+// columnBorderColor highlights the focused column's borders when more than
+// one column is shown.
+func (m *multimodelModel) columnBorderColor(index int) lipgloss.Color {
+	if index == m.focusedColumn && len(m.assignedColumns()) > 1 {
+		return lipgloss.Color("62")
+	}
+	return lipgloss.Color("238")
+}
+
+// multimodelChatView renders one column per assigned model, paging them
+// horizontally when they do not all fit in the terminal width.
 func (m *multimodelModel) multimodelChatView() string {
 	var builder strings.Builder
 
 	headerStyle := lipgloss.NewStyle().Background(lipgloss.Color("62")).Foreground(lipgloss.Color("230")).Padding(0, 1)
 	header := headerStyle.Render("Multimodel Chat")
-	help := lipgloss.NewStyle().Faint(true).Render(" (tab to reassign, q to quit)")
-	builder.WriteString(header + help + "\n\n")
+	help := lipgloss.NewStyle().Faint(true).Render(" (tab to reassign, q to quit, ctrl+t thoughts, ctrl+←/→ focus, pgup/pgdn scroll, ctrl+o zoom, ctrl+b vote, ctrl+x mute, @N to target)")
+	builder.WriteString(header + help)
+	if m.sharedHistory {
+		builder.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("205")).Render("  shared history"))
+	}
 
-	colWidth := (m.width - 8) / 4 // Account for borders and spacing
+	m.focusedViewport()
+	columns := m.pageColumns()
+	if m.zoomed && len(columns) > 0 {
+		columns = []int{m.focusedColumn}
+	} else if total := len(m.assignedColumns()); len(columns) < total {
+		first := m.columnOffset + 1
+		paging := fmt.Sprintf("  columns %d-%d of %d (shift+←/→ to scroll)", first, first+len(columns)-1, total)
+		builder.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("205")).Render(paging))
+	}
+	builder.WriteString("\n")
+	if len(m.similarities) > 0 && !m.isLoading {
+		builder.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("244")).Render(formatSimilarities(m.similarities, m.assignedColumns())))
+	}
+	builder.WriteString("\n")
+
+	if m.diff != nil {
+		builder.WriteString(m.diffView())
+		builder.WriteString(m.inputView())
+		return builder.String()
+	}
+
+	colWidth := 0
+	if len(columns) > 0 {
+		colWidth = (m.width - 2*len(columns)) / len(columns) // Account for borders
+	}
 
 	var headerCells []string
-	for i := 0; i < 4; i++ {
-		var colHeader string
-		if i < len(m.assignments) && m.assignments[i].isAssigned {
-			// Build stats line from response meta if available
-			stats := ""
-			if i < len(m.columnResponses) {
-				meta := m.columnResponses[i].meta
-				if meta.TotalDuration > 0 {
-					totalSecs := float64(meta.TotalDuration) / 1e9
-					var tps float64
-					if totalSecs > 0 {
-						tps = float64(meta.EvalCount) / totalSecs
-					}
-					stats = fmt.Sprintf("T/S: %.1f | Time: %.1fs", tps, totalSecs)
+	for _, i := range columns {
+		// Build stats line from response meta if available
+		stats := ""
+		if i < len(m.columnResponses) {
+			meta := m.columnResponses[i].meta
+			if meta.TotalDuration > 0 {
+				totalSecs := float64(meta.TotalDuration) / 1e9
+				var tps float64
+				if totalSecs > 0 {
+					tps = float64(meta.EvalCount) / totalSecs
 				}
+				stats = fmt.Sprintf("T/S: %.1f | Time: %.1fs", tps, totalSecs)
 			}
-			hostStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("62"))
-			modelStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("62"))
-			statsStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("238"))
-
-			colHeader = fmt.Sprintf(
-				"%s\n%s\n%s",
-				hostStyle.Render(m.assignments[i].host.Name),
-				modelStyle.Render(m.assignments[i].selectedModel),
-				statsStyle.Render(stats),
-			)
-		} else {
-			colHeader = "Empty\n\n"
 		}
+		hostStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("62"))
+		modelStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("62"))
+		statsStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("238"))
+
+		if m.laneSchema(i) != nil && i < len(m.columnResponses) {
+			stats += " " + m.columnResponses[i].schemaCheck.shortLabel()
+		}
+		if m.columnResponses[i].muted {
+			stats += lipgloss.NewStyle().Foreground(lipgloss.Color("244")).Render(" muted")
+		}
+		if score, rank, ok := m.judgeScoreFor(i); ok {
+			stats += lipgloss.NewStyle().Foreground(lipgloss.Color("214")).Render(fmt.Sprintf(" #%d %.1f", rank, score.score))
+		}
+		if rank, ok := m.votes[i]; ok {
+			vote := fmt.Sprintf(" vote #%d", rank)
+			if rank == 1 {
+				vote = " ★ best"
+			}
+			stats += lipgloss.NewStyle().Foreground(lipgloss.Color("205")).Render(vote)
+		}
+		if vp := m.columnResponses[i].viewport; !vp.AtBottom() {
+			stats += fmt.Sprintf(" ↕ %.0f%%", vp.ScrollPercent()*100)
+		}
+		colHeader := fmt.Sprintf(
+			"%s\n%s\n%s",
+			hostStyle.Render(fmt.Sprintf("[%d] %s", m.columnPosition(i), m.laneName(i))),
+			modelStyle.Render(m.assignments[i].selectedModel),
+			statsStyle.Render(stats),
+		)
 
 		colHeaderStyle := lipgloss.NewStyle().
 			Width(colWidth).
 			Height(3).
 			Border(lipgloss.NormalBorder()).
-			BorderForeground(lipgloss.Color("238")).
+			BorderForeground(m.columnBorderColor(i)).
 			Align(lipgloss.Center).
 			Bold(true)
 
@@ -660,45 +1114,77 @@ func (m *multimodelModel) multimodelChatView() string {
 	headerRow := lipgloss.JoinHorizontal(lipgloss.Top, headerCells...)
 	builder.WriteString(headerRow + "\n")
 
+	judgePanel := m.judgeView()
 	chatHeight := m.height - lipgloss.Height(headerRow) - lipgloss.Height(m.textArea.View()) - 10 // Adjust for padding/margins
+	if judgePanel != "" {
+		chatHeight -= lipgloss.Height(judgePanel) - 1
+	}
+	chatHeight = max(chatHeight, 1)
 
 	var chatRows []string
-	for i := 0; i < 4; i++ {
+	for _, i := range columns {
 		var colChatHistory strings.Builder
-		if i < len(m.assignments) && m.assignments[i].isAssigned {
-			if m.columnResponses[i].error != nil {
-				errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
-				colChatHistory.WriteString(errorStyle.Render(fmt.Sprintf("Error: %v", m.columnResponses[i].error)))
-			} else {
-				userStyle := lipgloss.NewStyle().Bold(true)
-				assistantStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("5"))
-
-				for _, msg := range m.columnResponses[i].chatHistory {
-					var role, content string
-					if msg.Role == "assistant" {
-						role = assistantStyle.Render("Assistant: ")
-						content = msg.Content
-					} else {
-						role = userStyle.Render("You: ")
-						content = msg.Content
+		if m.columnResponses[i].error != nil {
+			errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
+			colChatHistory.WriteString(errorStyle.Render(fmt.Sprintf("Error: %v", m.columnResponses[i].error)))
+		} else {
+			userStyle := lipgloss.NewStyle().Bold(true)
+			assistantStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("5"))
+
+			for _, msg := range m.columnResponses[i].chatHistory {
+				var role, content string
+				if msg.Role == "assistant" {
+					role = assistantStyle.Render("Assistant: ")
+					content = msg.Content
+					if m.config.JSON || m.laneSchema(i) != nil {
+						content = prettyJSON(content)
+					}
+					if section := renderThinking(msg.thinking, m.showThinking, colWidth-4); section != "" {
+						content = section + "\n" + content
 					}
-					wrappedContent := lipgloss.NewStyle().Width(colWidth - 2).Render(content)
-					colChatHistory.WriteString(role + "\n  " + wrappedContent + "\n\n")
+				} else {
+					role = userStyle.Render("You: ")
+					content = msg.Content
+				}
+				if len(msg.attachments) > 0 {
+					content += "\n[attached: " + strings.Join(msg.attachments, ", ") + "]"
 				}
+				wrappedContent := lipgloss.NewStyle().Width(colWidth - 4).Render(content)
+				colChatHistory.WriteString(role + "\n" + lipgloss.NewStyle().PaddingLeft(2).Render(wrappedContent) + "\n\n")
 			}
 		}
 
+		vp := &m.columnResponses[i].viewport
+		follow := vp.AtBottom()
+		vp.Width = colWidth - 2
+		vp.Height = chatHeight
+		vp.SetContent(colChatHistory.String())
+		if follow {
+			vp.GotoBottom()
+		}
+
 		colStyle := lipgloss.NewStyle().
 			Width(colWidth).
 			Height(chatHeight).
 			Border(lipgloss.NormalBorder()).
-			BorderForeground(lipgloss.Color("238")).
+			BorderForeground(m.columnBorderColor(i)).
 			Padding(0, 1)
 
-		chatRows = append(chatRows, colStyle.Render(colChatHistory.String()))
+		chatRows = append(chatRows, colStyle.Render(vp.View()))
 	}
 	builder.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, chatRows...) + "\n")
 
+	builder.WriteString(judgePanel)
+	builder.WriteString(m.inputView())
+
+	return builder.String()
+}
+
+// inputView renders the area below the columns: progress while streaming,
+// otherwise the notice, pending attachments and the input.
+func (m *multimodelModel) inputView() string {
+	var builder strings.Builder
+
 	var loadingIndicators []string
 	for i := range m.columnResponses {
 		if m.columnResponses[i].isStreaming {
@@ -710,17 +1196,90 @@ func (m *multimodelModel) multimodelChatView() string {
 	if m.isLoading {
 		builder.WriteString("\n" + strings.Join(loadingIndicators, "\n"))
 	} else {
+		if m.notice != "" {
+			builder.WriteString("\n" + lipgloss.NewStyle().Faint(true).Render(m.notice))
+		}
+		if len(m.attachments) > 0 {
+			builder.WriteString("\n" + lipgloss.NewStyle().Faint(true).Render("Attached: "+strings.Join(attachmentNames(m.attachments), ", ")))
+		}
 		builder.WriteString("\n" + m.textArea.View())
 	}
 
 	return builder.String()
 }
 
-// StartMultimodelGUI initializes and runs the four-column multimodel chat UI.
+// diffView renders the diff of the two columns compared with /diff in a
+// single full-width pane.
+func (m *multimodelModel) diffView() string {
+	level := "word"
+	if m.diff.lineLevel {
+		level = "line"
+	}
+	label := func(i int) string {
+		return fmt.Sprintf("[%d] %s / %s", m.columnPosition(i), m.laneName(i), m.assignments[i].selectedModel)
+	}
+	deleted := lipgloss.NewStyle().Foreground(lipgloss.Color("9")).Render("only in " + label(m.diff.a))
+	inserted := lipgloss.NewStyle().Foreground(lipgloss.Color("40")).Render("only in " + label(m.diff.b))
+	title := fmt.Sprintf("Diff (%s-level): %s ↔ %s\n%s  %s  (/diff off to close)",
+		level, label(m.diff.a), label(m.diff.b), deleted, inserted)
+
+	headerStyle := lipgloss.NewStyle().
+		Width(m.width - 2).
+		Border(lipgloss.NormalBorder()).
+		BorderForeground(lipgloss.Color("62")).
+		Bold(true)
+	header := headerStyle.Render(title)
+
+	height := m.height - lipgloss.Height(header) - lipgloss.Height(m.textArea.View()) - 10
+	vp := &m.diffViewport
+	vp.Width = m.width - 4
+	vp.Height = max(height, 1)
+	vp.SetContent(lipgloss.NewStyle().Width(m.width - 4).Render(renderDiff(m.diff.ops)))
+
+	paneStyle := lipgloss.NewStyle().
+		Width(m.width-2).
+		Height(vp.Height).
+		Border(lipgloss.NormalBorder()).
+		BorderForeground(lipgloss.Color("238")).
+		Padding(0, 1)
+
+	return header + "\n" + paneStyle.Render(vp.View()) + "\n"
+}
+
+// columnPosition returns the 1-based on-screen number of the column at index,
+// as used by /diff, or 0 if the column has no model assigned.
+func (m *multimodelModel) columnPosition(index int) int {
+	for p, i := range m.assignedColumns() {
+		if i == index {
+			return p + 1
+		}
+	}
+	return 0
+}
+
+// columnAt returns the lane index of the assigned column with the 1-based
+// on-screen number n.
+func (m *multimodelModel) columnAt(n int) (int, bool) {
+	cols := m.assignedColumns()
+	if n < 1 || n > len(cols) {
+		return 0, false
+	}
+	return cols[n-1], true
+}
+
+// StartMultimodelGUI initializes and runs the multimodel chat UI.
 // It accepts a parsed Config, sets up the Bubble Tea program, and blocks until
-// the UI exits. StartMultimodelGUI returns an error if the TUI cannot be run.
-func StartMultimodelGUI(cfg *Config) error {
+// the UI exits. When preset is set, its lanes are assigned and the chat starts
+// right away. StartMultimodelGUI returns an error if the preset does not exist
+// or the TUI cannot be run.
+func StartMultimodelGUI(cfg *Config, preset string) error {
 	m := initialMultimodelModel(cfg)
+	if preset != "" {
+		if err := m.applyPreset(preset); err != nil {
+			return err
+		}
+		m.startChat()
+	}
 	m.client = &http.Client{
 		Transport: &http.Transport{
 			ForceAttemptHTTP2: false,
@@ -2,8 +2,13 @@
 package cli
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -115,7 +120,7 @@ func TestMultimodelView(t *testing.T) {
 
 	// Test case 2: Error view
 	m.width = 100
-	m.err = multimodelChatReadyErr(errors.New("test error"))
+	m.err = errors.New("test error")
 	view = m.View()
 	if !strings.Contains(view, "Error") {
 		t.Errorf("Expected view to contain 'Error', got '%s'", view)
@@ -128,3 +133,143 @@ func TestMultimodelView(t *testing.T) {
 		t.Errorf("Expected view to contain 'Multimodel Mode - Assign Models to Hosts', got '%s'", view)
 	}
 }
+
+func TestMultimodelMoreThanFourColumns(t *testing.T) {
+	cfg := &Config{}
+	for i := 0; i < 6; i++ {
+		cfg.Hosts = append(cfg.Hosts, Host{Name: fmt.Sprintf("Host %d", i+1), URL: "http://x", Models: []string{"m"}})
+	}
+	m := initialMultimodelModel(cfg)
+	for i := range m.assignments {
+		m.assignments[i].selectedModel = "m"
+		m.assignments[i].isAssigned = true
+	}
+	m.state = multimodelViewChat
+	m.width, m.height = 130, 40
+
+	if len(m.columnResponses) != 6 {
+		t.Fatalf("expected a column per assignment, got %d", len(m.columnResponses))
+	}
+	m2, _ := m.Update(multimodelStreamChunkMsg{hostIndex: 5, message: chatMessage{Role: "assistant", Content: "sixth"}})
+	m = m2.(*multimodelModel)
+	if got := m.columnResponses[5].chatHistory[0].Content; got != "sixth" {
+		t.Fatalf("expected reply in the sixth column, got %q", got)
+	}
+
+	view := m.View()
+	if !strings.Contains(view, "columns 1-4 of 6") || strings.Contains(view, "Host 6") {
+		t.Fatalf("expected the first page of columns, got:\n%s", view)
+	}
+	for i := 0; i < 5; i++ {
+		m.Update(tea.KeyMsg{Type: tea.KeyShiftRight})
+	}
+	view = m.View()
+	if !strings.Contains(view, "columns 3-6 of 6") || !strings.Contains(view, "Host 6") {
+		t.Fatalf("expected to scroll to the last page, got:\n%s", view)
+	}
+}
+
+func TestMultimodelColumnScrollAndZoom(t *testing.T) {
+	cfg := &Config{Hosts: []Host{
+		{Name: "Left", URL: "http://a", Models: []string{"m"}},
+		{Name: "Right", URL: "http://b", Models: []string{"m"}},
+	}}
+	m := initialMultimodelModel(cfg)
+	for i := range m.assignments {
+		m.assignments[i].selectedModel = "m"
+		m.assignments[i].isAssigned = true
+	}
+	m.state = multimodelViewChat
+	m.width, m.height = 100, 30
+
+	long := strings.TrimSpace(strings.Repeat("line\n", 100))
+	m.Update(multimodelStreamChunkMsg{hostIndex: 1, message: chatMessage{Role: "assistant", Content: long + "\nlast line"}})
+	view := m.View()
+	if !strings.Contains(view, "last line") {
+		t.Fatalf("expected the column to follow the end of a long answer")
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlRight})
+	if m.focusedColumn != 1 {
+		t.Fatalf("expected focus on the second column, got %d", m.focusedColumn)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyPgUp})
+	view = m.View()
+	if strings.Contains(view, "last line") || m.columnResponses[1].viewport.AtBottom() {
+		t.Fatalf("expected the focused column to scroll up")
+	}
+	if !m.columnResponses[0].viewport.AtBottom() {
+		t.Fatalf("expected the other column to keep its position")
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlO})
+	view = m.View()
+	if strings.Contains(view, "Left") || !strings.Contains(view, "Right") {
+		t.Fatalf("expected only the focused column when zoomed, got:\n%s", view)
+	}
+}
+
+func TestMultimodelPreload(t *testing.T) {
+	var mu sync.Mutex
+	loaded := map[string]bool{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Model string `json:"model"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		if r.URL.Path != "/api/generate" || req.Model == "missing" {
+			http.Error(w, "model not found", http.StatusNotFound)
+			return
+		}
+		mu.Lock()
+		loaded[req.Model] = true
+		mu.Unlock()
+		w.Write([]byte(`{"done": true}`))
+	}))
+	defer server.Close()
+
+	cfg := &Config{Hosts: []Host{
+		{Name: "A", URL: server.URL, Models: []string{"good"}},
+		{Name: "B", URL: server.URL, Models: []string{"missing"}},
+	}}
+	m := initialMultimodelModel(cfg)
+	m.client = server.Client()
+	m.width, m.height = 120, 30
+	for i, model := range []string{"good", "missing"} {
+		m.assignments[i].selectedModel = model
+		m.assignments[i].isAssigned = true
+	}
+
+	if m.startChat() == nil || m.state != multimodelViewLoadingChat {
+		t.Fatalf("expected the chat to start loading")
+	}
+	if view := m.View(); !strings.Contains(view, "loading...") {
+		t.Fatalf("expected per-lane load status, got:\n%s", view)
+	}
+	for i := range m.assignments {
+		a := m.assignments[i]
+		m.Update(preloadLaneCmd(i, a.host, a.selectedModel, m.client)())
+	}
+	if !loaded["good"] {
+		t.Fatalf("expected the model to be preloaded")
+	}
+	if m.state != multimodelViewAssignment || m.assignments[0].loadState != laneLoaded || m.assignments[1].loadState != laneLoadFailed {
+		t.Fatalf("expected a failed lane to return to the assignment view, got state %d", m.state)
+	}
+	view := m.View()
+	if !strings.Contains(view, "✓ loaded in") || !strings.Contains(view, "✗ API returned non-200 status") {
+		t.Fatalf("expected load results in the assignment view, got:\n%s", view)
+	}
+
+	// Once every lane loads, the chat opens.
+	m.assignments[1].isAssigned = false
+	m.startChat()
+	_, cmd := m.Update(preloadLaneCmd(0, m.assignments[0].host, "good", m.client)())
+	if cmd == nil {
+		t.Fatalf("expected a ready message once all lanes loaded")
+	}
+	m.Update(cmd())
+	if m.state != multimodelViewChat {
+		t.Fatalf("expected the chat view, got state %d", m.state)
+	}
+}
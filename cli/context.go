@@ -0,0 +1,216 @@
+// cli/context.go
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// defaultNumCtx is Ollama's context window when num_ctx is not set.
+	defaultNumCtx = 2048
+	// defaultContextKeepLast is the number of recent messages kept verbatim
+	// by the keep_last and summarize strategies.
+	defaultContextKeepLast = 6
+	// contextWarnRatio is the share of the window at which the UI warns.
+	contextWarnRatio = 0.8
+	// contextFitRatio is the share of the window a request may fill before a
+	// strategy trims it, leaving room for the reply.
+	contextFitRatio = 0.75
+)
+
+// Context strategies accepted in Config.ContextStrategy.
+const (
+	contextStrategyDropOldest = "drop_oldest"
+	contextStrategyKeepLast   = "keep_last"
+	contextStrategySummarize  = "summarize"
+)
+
+// contextPolicy decides which part of the chat history is sent with a request
+// so that it fits within the model's context window.
+type contextPolicy struct {
+	strategy string
+	keepLast int
+	window   int
+	budget   int
+}
+
+// newContextPolicy builds the policy for a host from the configuration.
+func newContextPolicy(cfg *Config, params Parameters) contextPolicy {
+	window := contextWindow(params)
+	keepLast := cfg.ContextKeepLast
+	if keepLast <= 0 {
+		keepLast = defaultContextKeepLast
+	}
+	return contextPolicy{
+		strategy: cfg.ContextStrategy,
+		keepLast: keepLast,
+		window:   window,
+		budget:   int(float64(window) * contextFitRatio),
+	}
+}
+
+// contextWindow returns the configured num_ctx or Ollama's default.
+func contextWindow(params Parameters) int {
+	if params.NumCtx != nil && *params.NumCtx > 0 {
+		return *params.NumCtx
+	}
+	return defaultNumCtx
+}
+
+// estimateTokens approximates the token count of messages using the common
+// four-characters-per-token heuristic plus a small per-message overhead.
+func estimateTokens(msgs []chatMessage) int {
+	total := 0
+	for _, msg := range msgs {
+		total += len(msg.Content)/4 + 4
+	}
+	return total
+}
+
+// splitIndex returns the index where the verbatim tail of history starts.
+// The tail holds at least keepLast messages and begins on a user message.
+func (p contextPolicy) splitIndex(history []chatMessage) int {
+	split := len(history) - p.keepLast
+	if split <= 0 {
+		return 0
+	}
+	for split > 0 && history[split].Role != "user" {
+		split--
+	}
+	return split
+}
+
+// fit returns the messages to send for history. When the estimate exceeds the
+// budget, the configured strategy trims older turns. For the summarize
+// strategy, summary replaces the first covers messages; needSummary reports
+// that the summary is stale and must be regenerated before sending.
+func (p contextPolicy) fit(history []chatMessage, systemPrompt, summary string, covers int) (msgs []chatMessage, needSummary bool) {
+	overhead := estimateTokens([]chatMessage{{Content: systemPrompt}})
+	if p.strategy == "" || overhead+estimateTokens(history) <= p.budget {
+		return history, false
+	}
+
+	switch p.strategy {
+	case contextStrategyDropOldest:
+		start := 0
+		for start < len(history)-1 && overhead+estimateTokens(history[start:]) > p.budget {
+			start++
+		}
+		for start < len(history)-1 && history[start].Role != "user" {
+			start++
+		}
+		return history[start:], false
+
+	case contextStrategyKeepLast:
+		return history[p.splitIndex(history):], false
+
+	case contextStrategySummarize:
+		split := p.splitIndex(history)
+		if split == 0 {
+			return history, false
+		}
+		if summary == "" || covers != split {
+			return nil, true
+		}
+		msgs = append([]chatMessage{{Role: "system", Content: "Summary of the earlier conversation: " + summary}}, history[split:]...)
+		return msgs, false
+	}
+
+	return history, false
+}
+
+// historySummaryMsg carries a fresh summary of the first covers messages.
+type historySummaryMsg struct {
+	summary string
+	covers  int
+}
+
+// summarizeHistoryCmd asks the chat model to condense older messages into a
+// short summary that is sent in their place on later requests. The previous
+// summary, if any, is folded into the new one.
+func summarizeHistoryCmd(host Host, modelName, previous string, older []chatMessage, covers int, client *http.Client) tea.Cmd {
+	return func() tea.Msg {
+		var transcript strings.Builder
+		if previous != "" {
+			transcript.WriteString("Earlier summary: " + previous + "\n\n")
+		}
+		for _, msg := range older {
+			transcript.WriteString(fmt.Sprintf("%s: %s\n\n", msg.Role, msg.Content))
+		}
+
+		payload := map[string]any{
+			"model": modelName,
+			"messages": []chatMessage{
+				{Role: "system", Content: "Summarize the following conversation in a few sentences. Keep names, facts, decisions and open questions. Reply with the summary only."},
+				{Role: "user", Content: transcript.String()},
+			},
+			"stream": false,
+		}
+		body, _ := json.Marshal(payload)
+
+		req, err := http.NewRequestWithContext(context.Background(), "POST", host.URL+"/api/chat", bytes.NewReader(body))
+		if err != nil {
+			return streamErr(err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return streamErr(err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			bodyBytes, _ := io.ReadAll(resp.Body)
+			return streamErr(fmt.Errorf("summarize request returned non-200 status: %s. Body: %s", resp.Status, string(bodyBytes)))
+		}
+
+		var result streamChunk
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			return streamErr(err)
+		}
+		return historySummaryMsg{summary: strings.TrimSpace(result.Message.Content), covers: covers}
+	}
+}
+
+// sessionUsage renders the prompt tokens evaluated so far in the session for
+// the chat header, or nothing before the first reply.
+func sessionUsage(promptTokens int) string {
+	if promptTokens == 0 {
+		return ""
+	}
+	return lipgloss.NewStyle().Background(lipgloss.Color("0")).Foreground(lipgloss.Color("255")).Padding(0, 1).MarginLeft(1).
+		Render(fmt.Sprintf("Session: %d prompt tokens", promptTokens))
+}
+
+// contextGauge renders the context usage indicator for the chat header.
+// used is the token count of the last exchange; when it is unknown the
+// estimate of the current history is shown instead.
+func contextGauge(used, window int, estimated bool) string {
+	ratio := float64(used) / float64(window)
+	color := "40"
+	switch {
+	case ratio >= contextWarnRatio:
+		color = "9"
+	case ratio >= 0.6:
+		color = "214"
+	}
+	prefix := ""
+	if estimated {
+		prefix = "~"
+	}
+	label := fmt.Sprintf("Context: %s%d/%d (%.0f%%)", prefix, used, window, ratio*100)
+	if ratio >= contextWarnRatio {
+		label += " near limit"
+	}
+	return lipgloss.NewStyle().Background(lipgloss.Color("0")).Foreground(lipgloss.Color(color)).Padding(0, 1).MarginLeft(1).Render(label)
+}
@@ -0,0 +1,104 @@
+// cli/context_test.go
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func longHistory(turns int) []chatMessage {
+	var history []chatMessage
+	filler := strings.Repeat("word ", 100)
+	for i := 0; i < turns; i++ {
+		history = append(history,
+			chatMessage{Role: "user", Content: filler},
+			chatMessage{Role: "assistant", Content: filler},
+		)
+	}
+	return history
+}
+
+func TestContextPolicyFit(t *testing.T) {
+	numCtx := 1024
+	params := Parameters{NumCtx: &numCtx}
+	history := longHistory(10) // roughly 2600 estimated tokens
+
+	// No strategy sends everything.
+	p := newContextPolicy(&Config{}, params)
+	if msgs, _ := p.fit(history, "", "", 0); len(msgs) != len(history) {
+		t.Fatalf("expected full history without a strategy, got %d messages", len(msgs))
+	}
+
+	// drop_oldest trims from the front until the estimate fits.
+	p = newContextPolicy(&Config{ContextStrategy: contextStrategyDropOldest}, params)
+	msgs, _ := p.fit(history, "", "", 0)
+	if estimateTokens(msgs) > p.budget || msgs[0].Role != "user" {
+		t.Fatalf("expected trimmed history starting with a user message; got %d tokens, first role %q", estimateTokens(msgs), msgs[0].Role)
+	}
+
+	// keep_last keeps only the recent tail.
+	p = newContextPolicy(&Config{ContextStrategy: contextStrategyKeepLast, ContextKeepLast: 4}, params)
+	if msgs, _ = p.fit(history, "", "", 0); len(msgs) != 4 {
+		t.Fatalf("expected 4 messages with keep_last, got %d", len(msgs))
+	}
+
+	// summarize asks for a summary, then substitutes it for older turns.
+	p = newContextPolicy(&Config{ContextStrategy: contextStrategySummarize, ContextKeepLast: 2}, params)
+	if _, need := p.fit(history, "", "", 0); !need {
+		t.Fatal("expected summarize strategy to request a summary")
+	}
+	split := p.splitIndex(history)
+	msgs, need := p.fit(history, "", "earlier things", split)
+	if need || len(msgs) != 3 || msgs[0].Role != "system" || !strings.Contains(msgs[0].Content, "earlier things") {
+		t.Fatalf("expected summary plus the last 2 messages; need=%v msgs=%v", need, msgs)
+	}
+}
+
+func TestContextWindowDefault(t *testing.T) {
+	if got := contextWindow(Parameters{}); got != defaultNumCtx {
+		t.Fatalf("expected default window %d, got %d", defaultNumCtx, got)
+	}
+}
+
+func TestSessionUsageShownAfterReplies(t *testing.T) {
+	cfg := &Config{Hosts: []Host{{Name: "HostA", URL: "http://x", Models: []string{"m1"}}}}
+	m := initialModel(cfg)
+	m.state = viewChat
+	m.width, m.height = 200, 40
+
+	if strings.Contains(m.View(), "Session:") {
+		t.Fatal("expected no session usage before the first reply")
+	}
+	for _, n := range []int{120, 80} {
+		m2, _ := m.Update(streamEndMsg{meta: LLMResponseMeta{Done: true, PromptEvalCount: n}})
+		m = m2.(*model)
+	}
+	if !strings.Contains(m.View(), "Session: 200 prompt tokens") {
+		t.Fatalf("expected cumulative prompt tokens in the header, got:\n%s", m.View())
+	}
+}
+
+func TestContextGaugeEstimatedAfterBranchSwitch(t *testing.T) {
+	cfg := &Config{Hosts: []Host{{Name: "HostA", URL: "http://x", Models: []string{"m1"}}}}
+	m := initialModel(cfg)
+	m.state = viewChat
+	m.width, m.height = 200, 40
+
+	m.session.append(chatMessage{Role: "user", Content: "q1"})
+	m.session.fork(0, chatMessage{Role: "user", Content: "other"})
+	m.chatHistory = m.session.path()
+	m2, _ := m.Update(streamEndMsg{meta: LLMResponseMeta{Done: true, PromptEvalCount: 900, EvalCount: 100}})
+	m = m2.(*model)
+	if !strings.Contains(m.View(), "Context: 1000/") {
+		t.Fatalf("expected the measured usage in the header, got:\n%s", m.View())
+	}
+
+	m.textArea.SetValue("/prev")
+	m2, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = m2.(*model)
+	if view := m.View(); strings.Contains(view, "Context: 1000/") || !strings.Contains(view, "Context: ~") {
+		t.Fatalf("expected an estimate for the new branch, got:\n%s", view)
+	}
+}
@@ -0,0 +1,281 @@
+// cli/diff.go
+package cli
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// maxDiffCells bounds the size of the LCS table for word-level diffs. Larger
+// inputs fall back to a line-level diff.
+const maxDiffCells = 4_000_000
+
+// diffKind classifies a span of a diff.
+type diffKind int
+
+const (
+	// diffEqual is text present in both responses
+	diffEqual diffKind = iota
+	// diffDelete is text only in the first response
+	diffDelete
+	// diffInsert is text only in the second response
+	diffInsert
+)
+
+// diffOp is a run of tokens with the same diffKind.
+type diffOp struct {
+	kind diffKind
+	text string
+}
+
+// wordPattern splits text into words and the whitespace between them, so a
+// word-level diff can be joined back together without losing formatting.
+var wordPattern = regexp.MustCompile(`\S+|\s+`)
+
+// diffTokens splits text into the tokens compared by a word- or line-level diff.
+func diffTokens(text string, lines bool) []string {
+	if lines {
+		return strings.SplitAfter(text, "\n")
+	}
+	return wordPattern.FindAllString(text, -1)
+}
+
+// diffResponses compares two responses word by word, or line by line when
+// lines is set or the responses are too long for a word-level diff. It
+// reports whether a line-level diff was used.
+func diffResponses(a, b string, lines bool) ([]diffOp, bool) {
+	ta, tb := diffTokens(a, lines), diffTokens(b, lines)
+	if !lines && len(ta)*len(tb) > maxDiffCells {
+		return diffResponses(a, b, true)
+	}
+	return diffSequences(ta, tb), lines
+}
+
+// diffSequences computes a longest-common-subsequence diff of a and b and
+// merges adjacent tokens of the same kind.
+func diffSequences(a, b []string) []diffOp {
+	// Common prefix and suffix are trimmed so the table only covers the
+	// part of the responses that differs.
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+
+	// lcs[i][j] is the LCS length of midA[i:] and midB[j:].
+	lcs := make([][]int32, len(midA)+1)
+	for i := range lcs {
+		lcs[i] = make([]int32, len(midB)+1)
+	}
+	for i := len(midA) - 1; i >= 0; i-- {
+		for j := len(midB) - 1; j >= 0; j-- {
+			if midA[i] == midB[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var ops []diffOp
+	emit := func(kind diffKind, text string) {
+		if n := len(ops); n > 0 && ops[n-1].kind == kind {
+			ops[n-1].text += text
+			return
+		}
+		ops = append(ops, diffOp{kind: kind, text: text})
+	}
+
+	emit(diffEqual, strings.Join(a[:prefix], ""))
+	i, j := 0, 0
+	for i < len(midA) || j < len(midB) {
+		switch {
+		case i < len(midA) && j < len(midB) && midA[i] == midB[j]:
+			emit(diffEqual, midA[i])
+			i++
+			j++
+		case i < len(midA) && (j == len(midB) || lcs[i+1][j] >= lcs[i][j+1]):
+			emit(diffDelete, midA[i])
+			i++
+		default:
+			emit(diffInsert, midB[j])
+			j++
+		}
+	}
+	emit(diffEqual, strings.Join(a[len(a)-suffix:], ""))
+
+	// Drop the empty spans left by an empty prefix or suffix.
+	kept := ops[:0]
+	for _, op := range ops {
+		if op.text != "" {
+			kept = append(kept, op)
+		}
+	}
+	return kept
+}
+
+// renderDiff renders ops with deletions in red and insertions in green.
+func renderDiff(ops []diffOp) string {
+	deleted := lipgloss.NewStyle().Foreground(lipgloss.Color("9")).Strikethrough(true)
+	inserted := lipgloss.NewStyle().Foreground(lipgloss.Color("40")).Underline(true)
+
+	var b strings.Builder
+	for _, op := range ops {
+		switch op.kind {
+		case diffEqual:
+			b.WriteString(op.text)
+		case diffDelete:
+			b.WriteString(styleLines(deleted, op.text))
+		case diffInsert:
+			b.WriteString(styleLines(inserted, op.text))
+		}
+	}
+	return b.String()
+}
+
+// styleLines applies style to each line of text separately so that styling
+// does not leak across line breaks when the result is wrapped.
+func styleLines(style lipgloss.Style, text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = style.Render(line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// similarity holds how alike two responses are.
+type similarity struct {
+	// a and b are the lane indices of the compared columns.
+	a, b int
+	// overlap is the Jaccard index of the sets of lower-cased words.
+	overlap float64
+	// edit is one minus the word-level edit distance divided by the length
+	// of the longer response.
+	edit float64
+}
+
+// compareResponses computes the similarity of two responses.
+func compareResponses(a, b string) similarity {
+	wa := strings.Fields(strings.ToLower(a))
+	wb := strings.Fields(strings.ToLower(b))
+	return similarity{overlap: jaccard(wa, wb), edit: editSimilarity(wa, wb)}
+}
+
+// jaccard returns the size of the intersection of the word sets of a and b
+// divided by the size of their union.
+func jaccard(a, b []string) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+	setA := map[string]bool{}
+	for _, w := range a {
+		setA[w] = true
+	}
+	setB := map[string]bool{}
+	for _, w := range b {
+		setB[w] = true
+	}
+	shared := 0
+	for w := range setA {
+		if setB[w] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(setA)+len(setB)-shared)
+}
+
+// editSimilarity returns 1 - levenshtein(a, b) / max(len(a), len(b)) over words.
+func editSimilarity(a, b []string) float64 {
+	longest := max(len(a), len(b))
+	if longest == 0 {
+		return 1
+	}
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return 1 - float64(prev[len(b)])/float64(longest)
+}
+
+// diffState is the pair of columns compared in diff mode.
+type diffState struct {
+	// a and b are the lane indices of the compared columns.
+	a, b int
+	// lines requests a line-level diff instead of a word-level one.
+	lines bool
+	// ops is the diff of the latest responses of a and b.
+	ops []diffOp
+	// lineLevel reports whether ops is line-level, either because it was
+	// requested or because the responses were too long to diff by word.
+	lineLevel bool
+}
+
+// latestResponse returns the last assistant answer in the column at index.
+func (m *multimodelModel) latestResponse(index int) string {
+	history := m.columnResponses[index].chatHistory
+	for i := len(history) - 1; i >= 0; i-- {
+		if history[i].Role == "assistant" {
+			return history[i].Content
+		}
+	}
+	return ""
+}
+
+// refreshComparisons recomputes the pairwise similarities of the latest
+// responses and, in diff mode, the diff of the compared columns.
+func (m *multimodelModel) refreshComparisons() {
+	m.similarities = nil
+	var answered []int
+	for _, i := range m.assignedColumns() {
+		if m.latestResponse(i) != "" {
+			answered = append(answered, i)
+		}
+	}
+	for x := 0; x < len(answered); x++ {
+		for y := x + 1; y < len(answered); y++ {
+			sim := compareResponses(m.latestResponse(answered[x]), m.latestResponse(answered[y]))
+			sim.a, sim.b = answered[x], answered[y]
+			m.similarities = append(m.similarities, sim)
+		}
+	}
+
+	if m.diff != nil {
+		m.diff.ops, m.diff.lineLevel = diffResponses(m.latestResponse(m.diff.a), m.latestResponse(m.diff.b), m.diff.lines)
+		m.diffViewport.GotoTop()
+	}
+}
+
+// formatSimilarities renders pairwise similarities for the header, labelling
+// columns by their on-screen position in cols.
+func formatSimilarities(sims []similarity, cols []int) string {
+	position := map[int]int{}
+	for p, i := range cols {
+		position[i] = p + 1
+	}
+	parts := make([]string, 0, len(sims))
+	for _, s := range sims {
+		parts = append(parts, fmt.Sprintf("%d↔%d %.0f%%/%.0f%%", position[s.a], position[s.b], s.overlap*100, s.edit*100))
+	}
+	return "Similarity (word overlap/edit): " + strings.Join(parts, "  ")
+}
@@ -0,0 +1,105 @@
+// cli/diff_test.go
+package cli
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+// applyDiff rebuilds both inputs of a diff from its ops.
+func applyDiff(ops []diffOp) (a, b string) {
+	var sa, sb strings.Builder
+	for _, op := range ops {
+		if op.kind != diffInsert {
+			sa.WriteString(op.text)
+		}
+		if op.kind != diffDelete {
+			sb.WriteString(op.text)
+		}
+	}
+	return sa.String(), sb.String()
+}
+
+func TestDiffResponses(t *testing.T) {
+	a := "The quick brown fox\njumps over the dog."
+	b := "The quick red fox\njumps over the lazy dog."
+
+	ops, lineLevel := diffResponses(a, b, false)
+	if lineLevel {
+		t.Fatal("expected a word-level diff")
+	}
+	if gotA, gotB := applyDiff(ops); gotA != a || gotB != b {
+		t.Fatalf("diff does not reproduce its inputs: %q, %q", gotA, gotB)
+	}
+	var deleted, inserted []string
+	for _, op := range ops {
+		switch op.kind {
+		case diffDelete:
+			deleted = append(deleted, op.text)
+		case diffInsert:
+			inserted = append(inserted, op.text)
+		}
+	}
+	if strings.Join(deleted, "|") != "brown" || strings.Join(inserted, "|") != "red| lazy" {
+		t.Fatalf("unexpected changes: deleted %q, inserted %q", deleted, inserted)
+	}
+
+	ops, lineLevel = diffResponses(a, b, true)
+	if !lineLevel || len(ops) != 2 || ops[0].kind != diffDelete {
+		t.Fatalf("expected both lines replaced in a line-level diff, got %+v", ops)
+	}
+}
+
+func TestCompareResponses(t *testing.T) {
+	same := compareResponses("Hello world", "hello  WORLD")
+	if same.overlap != 1 || same.edit != 1 {
+		t.Fatalf("expected identical responses to score 1, got %+v", same)
+	}
+	sim := compareResponses("a b c d", "a b x")
+	if math.Abs(sim.overlap-0.4) > 1e-9 || math.Abs(sim.edit-0.5) > 1e-9 {
+		t.Fatalf("unexpected similarity %+v", sim)
+	}
+}
+
+func TestMultimodelDiffCommand(t *testing.T) {
+	cfg := &Config{Hosts: []Host{
+		{Name: "A", URL: "http://a", Models: []string{"m"}},
+		{Name: "B", URL: "http://b", Models: []string{"m"}},
+		{Name: "C", URL: "http://c", Models: []string{"m"}},
+	}}
+	m := initialMultimodelModel(cfg)
+	for i := range m.assignments {
+		m.assignments[i].selectedModel = "m"
+		m.assignments[i].isAssigned = true
+	}
+	m.state = multimodelViewChat
+	m.width, m.height = 120, 40
+	for i, answer := range []string{"the sky is blue", "the sky is grey", "the sky is blue"} {
+		m.columnResponses[i].chatHistory = []chatMessage{{Role: "user", Content: "sky?"}, {Role: "assistant", Content: answer}}
+	}
+	m.Update(multimodelStreamEndMsg{hostIndex: 0})
+
+	if len(m.similarities) != 3 {
+		t.Fatalf("expected similarities for 3 pairs, got %d", len(m.similarities))
+	}
+	if view := m.View(); !strings.Contains(view, "1↔3 100%/100%") {
+		t.Fatalf("expected similarity scores in header, got:\n%s", view)
+	}
+
+	m.runMultimodelCommand("/diff 1 4")
+	if m.diff != nil || !strings.Contains(m.notice, "No column 4") {
+		t.Fatalf("expected an error for an unknown column, got %q", m.notice)
+	}
+	m.runMultimodelCommand("/diff 1 2")
+	if m.diff == nil || m.diff.a != 0 || m.diff.b != 1 {
+		t.Fatalf("expected diff mode between columns 1 and 2")
+	}
+	if view := m.View(); !strings.Contains(view, "Diff (word-level)") || !strings.Contains(view, "grey") {
+		t.Fatalf("expected the diff pane, got:\n%s", view)
+	}
+	m.runMultimodelCommand("/diff off")
+	if m.diff != nil {
+		t.Fatal("expected diff mode to be closed")
+	}
+}
@@ -0,0 +1,114 @@
+// cli/history.go
+package cli
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// requestHistory returns the messages sent to the lane at index: its own
+// history, or in shared history mode the prompts together with every lane's
+// answers.
+func (m *multimodelModel) requestHistory(index int) []chatMessage {
+	if m.sharedHistory {
+		return m.chatHistory
+	}
+	return m.columnResponses[index].chatHistory
+}
+
+// clearColumns handles /clear. Without arguments every column and the shared
+// history are reset; otherwise only the listed columns start over. Lanes are
+// sent the shared history in shared history mode, so single columns cannot be
+// cleared then.
+func (m *multimodelModel) clearColumns(args []string) string {
+	if m.isLoading {
+		return "Wait for the current round to finish."
+	}
+	if m.sharedHistory && len(args) > 0 {
+		return "Columns share one history; use /clear to clear all of them, or /shared to switch back to isolated histories."
+	}
+	lanes := m.assignedColumns()
+	if len(args) > 0 {
+		lanes = nil
+		for _, arg := range args {
+			n, err := strconv.Atoi(arg)
+			i, ok := m.columnAt(n)
+			if err != nil || !ok {
+				return fmt.Sprintf("No column %s.", arg)
+			}
+			lanes = append(lanes, i)
+		}
+	} else {
+		m.chatHistory = nil
+	}
+
+	for _, i := range lanes {
+		col := &m.columnResponses[i]
+		col.chatHistory = nil
+		col.content.Reset()
+		col.error = nil
+		col.schemaCheck = schemaResult{}
+		col.meta = LLMResponseMeta{}
+		col.viewport.SetContent("")
+		col.viewport.GotoTop()
+	}
+	m.refreshComparisons()
+	m.judgement = nil
+	m.votes = nil
+	if len(args) == 0 {
+		return "Cleared all columns."
+	}
+	return fmt.Sprintf("Cleared %d column(s).", len(lanes))
+}
+
+// syncHistories handles /sync N: the latest answer of column N replaces the
+// latest answer in every other column's history, and in the shared history,
+// so that follow-up questions build on the same answer.
+func (m *multimodelModel) syncHistories(args []string) string {
+	if m.isLoading {
+		return "Wait for the current round to finish."
+	}
+	if len(args) != 1 {
+		return "Usage: /sync <column>"
+	}
+	n, err := strconv.Atoi(args[0])
+	source, ok := m.columnAt(n)
+	if err != nil || !ok {
+		return fmt.Sprintf("No column %s.", args[0])
+	}
+	answer := m.latestResponse(source)
+	if answer == "" {
+		return fmt.Sprintf("Column %d has no answer to sync.", n)
+	}
+
+	synced := 0
+	for _, i := range m.assignedColumns() {
+		if i == source {
+			continue
+		}
+		if replaceLastAnswer(&m.columnResponses[i].chatHistory, answer) {
+			synced++
+		}
+	}
+	replaceLastAnswer(&m.chatHistory, answer)
+	m.refreshComparisons()
+	m.judgement = nil
+	m.votes = nil
+	return fmt.Sprintf("Synced the answer of column %d into %d other column(s).", n, synced)
+}
+
+// replaceLastAnswer makes answer the reply to the last prompt in history,
+// replacing an existing reply or appending one after an unanswered prompt.
+// It reports whether history changed.
+func replaceLastAnswer(history *[]chatMessage, answer string) bool {
+	h := *history
+	switch {
+	case len(h) == 0:
+		return false
+	case h[len(h)-1].Role == "assistant":
+		h[len(h)-1] = chatMessage{Role: "assistant", Content: answer}
+	default:
+		*history = append(h, chatMessage{Role: "assistant", Content: answer})
+	}
+	return true
+}
@@ -0,0 +1,72 @@
+// cli/history_test.go
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestMultimodelHistoryControls(t *testing.T) {
+	cfg := &Config{Hosts: []Host{
+		{Name: "A", URL: "http://a", Models: []string{"m"}},
+		{Name: "B", URL: "http://b", Models: []string{"m"}},
+	}}
+	m := initialMultimodelModel(cfg)
+	for i := range m.assignments {
+		m.assignments[i].selectedModel = "m"
+		m.assignments[i].isAssigned = true
+	}
+	m.state = multimodelViewChat
+	m.width, m.height = 120, 30
+	round := func(prompt string, answers ...string) {
+		m.textArea.SetValue(prompt)
+		m.updateChat(tea.KeyMsg{Type: tea.KeyEnter})
+		for i, answer := range answers {
+			m.Update(multimodelStreamChunkMsg{hostIndex: i, message: chatMessage{Role: "assistant", Content: answer}})
+			m.Update(multimodelStreamEndMsg{hostIndex: i})
+		}
+	}
+
+	round("capital of France?", "Paris", "Lyon")
+	if len(m.chatHistory) != 2 || m.chatHistory[0].Content != "capital of France?" || !strings.Contains(m.chatHistory[1].Content, "Lyon") {
+		t.Fatalf("expected the shared history to hold the prompt and combined answers, got %+v", m.chatHistory)
+	}
+	if got := m.requestHistory(1); len(got) != 2 || got[1].Content != "Lyon" {
+		t.Fatalf("expected isolated lanes to see only their own history, got %+v", got)
+	}
+	m.runMultimodelCommand("/shared")
+	if got := m.requestHistory(1); len(got) != 2 || !strings.Contains(got[1].Content, "Paris") {
+		t.Fatalf("expected shared lanes to see every answer, got %+v", got)
+	}
+	m.runMultimodelCommand("/shared")
+
+	m.runMultimodelCommand("/sync 1")
+	if got := m.columnResponses[1].chatHistory; len(got) != 2 || got[1].Content != "Paris" {
+		t.Fatalf("expected column 2 to adopt column 1's answer, got %+v", got)
+	}
+	if m.chatHistory[1].Content != "Paris" {
+		t.Fatalf("expected the shared history to adopt column 1's answer, got %q", m.chatHistory[1].Content)
+	}
+
+	m.runMultimodelCommand("/shared")
+	m.runMultimodelCommand("/clear 2")
+	if len(m.columnResponses[1].chatHistory) != 2 || !strings.Contains(m.notice, "share one history") {
+		t.Fatalf("expected clearing one column of the shared history to be refused, got %q", m.notice)
+	}
+	m.runMultimodelCommand("/shared")
+
+	m.runMultimodelCommand("/clear 2")
+	if len(m.columnResponses[1].chatHistory) != 0 || len(m.columnResponses[0].chatHistory) != 2 || len(m.chatHistory) != 2 {
+		t.Fatalf("expected only column 2 to be cleared")
+	}
+	m.runMultimodelCommand("/sync 2")
+	if !strings.Contains(m.notice, "no answer") {
+		t.Fatalf("expected syncing an empty column to be refused, got %q", m.notice)
+	}
+	m.runMultimodelCommand("/clear")
+	if len(m.columnResponses[0].chatHistory) != 0 || len(m.chatHistory) != 0 {
+		t.Fatalf("expected every history to be cleared")
+	}
+}
@@ -0,0 +1,279 @@
+// cli/judge.go
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// defaultJudgeRubric is used when the judge configuration has no rubric.
+const defaultJudgeRubric = "Score each answer from 0 to 10 for correctness, completeness and clarity. Prefer concise answers when they are equally correct."
+
+// JudgeConfig selects the model that scores multimodel answers.
+type JudgeConfig struct {
+	// Host is the name or URL of a configured host that runs the judge.
+	Host string `json:"host"`
+	// Model is the judge model.
+	Model string `json:"model"`
+	// Rubric tells the judge how to score the answers.
+	Rubric string `json:"rubric"`
+	// Auto sends every completed round to the judge. Otherwise rounds are
+	// only judged on request with /judge.
+	Auto bool `json:"auto"`
+	// host is the resolved Host, set when the config is loaded.
+	host Host
+}
+
+// judgeSchema constrains the judge's reply to one score per answer.
+var judgeSchema = map[string]any{
+	"type":     "object",
+	"required": []any{"scores"},
+	"properties": map[string]any{
+		"scores": map[string]any{
+			"type": "array",
+			"items": map[string]any{
+				"type":     "object",
+				"required": []any{"answer", "score", "rationale"},
+				"properties": map[string]any{
+					"answer":    map[string]any{"type": "integer"},
+					"score":     map[string]any{"type": "number"},
+					"rationale": map[string]any{"type": "string"},
+				},
+			},
+		},
+	},
+}
+
+// judgeScore is the judge's verdict on one column.
+type judgeScore struct {
+	// lane is the lane index of the judged column.
+	lane int
+	// label names the lane's host and model, for the scoreboard.
+	label     string
+	score     float64
+	rationale string
+}
+
+// judgeResultMsg carries the scores of a judged round, sorted best first.
+// seq, turn and layout identify the request and the round it judged, so a
+// late verdict is not attached to a newer round or to shifted lanes.
+type judgeResultMsg struct {
+	scores []judgeScore
+	err    error
+
+	seq, turn, layout int
+}
+
+// scoreboardEntry accumulates a lane's results over a session.
+type scoreboardEntry struct {
+	label  string
+	rounds int
+	wins   int
+	total  float64
+}
+
+// judgeCandidate is an answer sent to the judge.
+type judgeCandidate struct {
+	lane   int
+	label  string
+	answer string
+}
+
+// buildJudgePrompt lays out the question and the anonymized answers for the
+// judge. Answers are numbered from 1 in the order of candidates.
+func buildJudgePrompt(question string, candidates []judgeCandidate) string {
+	var b strings.Builder
+	b.WriteString("Question:\n" + question + "\n")
+	for i, c := range candidates {
+		b.WriteString(fmt.Sprintf("\nAnswer %d:\n%s\n", i+1, c.answer))
+	}
+	b.WriteString("\nReturn one score per answer, using the answer number.")
+	return b.String()
+}
+
+// judgeCmd sends a round of answers to the judge and returns a
+// judgeResultMsg with the scores mapped back to their lanes.
+func judgeCmd(judge *JudgeConfig, question string, candidates []judgeCandidate, client *http.Client) tea.Cmd {
+	return func() tea.Msg {
+		rubric := judge.Rubric
+		if rubric == "" {
+			rubric = defaultJudgeRubric
+		}
+		zero := 0.0
+		r := chatRequest{
+			model:        judge.Model,
+			history:      []chatMessage{{Role: "user", Content: buildJudgePrompt(question, candidates)}},
+			systemPrompt: "You are an impartial judge comparing answers to the same question. " + rubric,
+			schema:       judgeSchema,
+			parameters:   Parameters{Temperature: &zero},
+		}
+
+		resp, err := postChat(context.Background(), judge.host, r, client)
+		if err != nil {
+			return judgeResultMsg{err: err}
+		}
+		defer resp.Body.Close()
+
+		var content strings.Builder
+		if _, err := decodeChatStream(resp.Body, func(chunk streamChunk) {
+			content.WriteString(chunk.Message.Content)
+		}); err != nil {
+			return judgeResultMsg{err: err}
+		}
+
+		scores, err := parseJudgeScores(content.String(), candidates)
+		return judgeResultMsg{scores: scores, err: err}
+	}
+}
+
+// parseJudgeScores decodes the judge's reply and maps answer numbers back to
+// the candidates' lanes. The scores are sorted best first.
+func parseJudgeScores(content string, candidates []judgeCandidate) ([]judgeScore, error) {
+	var reply struct {
+		Scores []struct {
+			Answer    int     `json:"answer"`
+			Score     float64 `json:"score"`
+			Rationale string  `json:"rationale"`
+		} `json:"scores"`
+	}
+	if err := json.Unmarshal([]byte(content), &reply); err != nil {
+		return nil, fmt.Errorf("judge reply is not valid JSON: %w", err)
+	}
+
+	seen := map[int]bool{}
+	var scores []judgeScore
+	for _, s := range reply.Scores {
+		if s.Answer < 1 || s.Answer > len(candidates) || seen[s.Answer] {
+			continue
+		}
+		seen[s.Answer] = true
+		c := candidates[s.Answer-1]
+		scores = append(scores, judgeScore{lane: c.lane, label: c.label, score: s.Score, rationale: strings.TrimSpace(s.Rationale)})
+	}
+	if len(scores) == 0 {
+		return nil, fmt.Errorf("judge reply contained no usable scores")
+	}
+	sort.SliceStable(scores, func(i, j int) bool { return scores[i].score > scores[j].score })
+	return scores, nil
+}
+
+// startJudging sends the latest round to the judge. Only columns that were
+// sent the latest prompt and answered it take part. It returns nil and sets
+// a notice when there is nothing to judge.
+func (m *multimodelModel) startJudging() tea.Cmd {
+	if m.config.Judge == nil {
+		m.notice = "No judge configured. Add a \"judge\" section to the config."
+		return nil
+	}
+	question := ""
+	var candidates []judgeCandidate
+	for _, i := range m.roundColumns() {
+		col := m.columnResponses[i]
+		answer := m.latestResponse(i)
+		u := lastIndexOfRole(col.chatHistory, "user")
+		if col.turn != m.turn || answer == "" || u < 0 {
+			continue
+		}
+		if question == "" {
+			question = col.chatHistory[u].Content
+		} else if col.chatHistory[u].Content != question {
+			continue
+		}
+		label := m.laneName(i) + " / " + m.assignments[i].selectedModel
+		candidates = append(candidates, judgeCandidate{lane: i, label: label, answer: answer})
+	}
+	if question == "" || len(candidates) < 2 {
+		m.notice = "Judging needs at least two answers to the last prompt."
+		return nil
+	}
+
+	m.judging = true
+	m.judgement = nil
+	m.notice = ""
+	m.judgeSeq++
+	seq, turn, layout := m.judgeSeq, m.turn, m.laneLayout
+	judge := judgeCmd(m.config.Judge, question, candidates, m.client)
+	return func() tea.Msg {
+		msg := judge().(judgeResultMsg)
+		msg.seq, msg.turn, msg.layout = seq, turn, layout
+		return msg
+	}
+}
+
+// recordJudgement stores a judged round and adds it to the scoreboard.
+func (m *multimodelModel) recordJudgement(scores []judgeScore) {
+	m.judgement = scores
+	if m.scoreboard == nil {
+		m.scoreboard = map[string]*scoreboardEntry{}
+	}
+	for rank, s := range scores {
+		entry, ok := m.scoreboard[s.label]
+		if !ok {
+			entry = &scoreboardEntry{label: s.label}
+			m.scoreboard[s.label] = entry
+		}
+		entry.rounds++
+		entry.total += s.score
+		if rank == 0 || s.score == scores[0].score {
+			entry.wins++
+		}
+	}
+}
+
+// judgeScoreFor returns the judge's score and rank (from 1) for the lane.
+func (m *multimodelModel) judgeScoreFor(lane int) (judgeScore, int, bool) {
+	for rank, s := range m.judgement {
+		if s.lane == lane {
+			return s, rank + 1, true
+		}
+	}
+	return judgeScore{}, 0, false
+}
+
+// judgeView renders the ranking and rationale of the last judged round, or
+// the session scoreboard when it is toggled on with /scoreboard.
+func (m *multimodelModel) judgeView() string {
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("214"))
+	lineStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("244"))
+	width := max(m.width-2, 20)
+
+	var lines []string
+	switch {
+	case m.showScoreboard:
+		lines = append(lines, titleStyle.Render("Scoreboard"))
+		entries := make([]*scoreboardEntry, 0, len(m.scoreboard))
+		for _, e := range m.scoreboard {
+			entries = append(entries, e)
+		}
+		sort.Slice(entries, func(i, j int) bool {
+			if entries[i].wins != entries[j].wins {
+				return entries[i].wins > entries[j].wins
+			}
+			return entries[i].total/float64(entries[i].rounds) > entries[j].total/float64(entries[j].rounds)
+		})
+		for _, e := range entries {
+			lines = append(lines, lineStyle.Render(truncate(fmt.Sprintf("%s: %d/%d wins, average %.1f", e.label, e.wins, e.rounds, e.total/float64(e.rounds)), width)))
+		}
+		if len(entries) == 0 {
+			lines = append(lines, lineStyle.Render("No rounds judged yet."))
+		}
+	case m.judging:
+		lines = append(lines, titleStyle.Render(fmt.Sprintf("Judging with %s...", m.config.Judge.Model)))
+	case len(m.judgement) > 0:
+		lines = append(lines, titleStyle.Render("Judge ("+m.config.Judge.Model+")"))
+		for rank, s := range m.judgement {
+			line := fmt.Sprintf("#%d [%d] %s: %.1f – %s", rank+1, m.columnPosition(s.lane), s.label, s.score, s.rationale)
+			lines = append(lines, lineStyle.Render(truncate(line, width)))
+		}
+	default:
+		return ""
+	}
+	return "\n" + strings.Join(lines, "\n")
+}
@@ -0,0 +1,174 @@
+// cli/judge_test.go
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseJudgeScores(t *testing.T) {
+	candidates := []judgeCandidate{{lane: 0, label: "A"}, {lane: 2, label: "C"}}
+	scores, err := parseJudgeScores(`{"scores":[{"answer":1,"score":6,"rationale":"ok"},{"answer":2,"score":9,"rationale":" best "},{"answer":7,"score":10}]}`, candidates)
+	if err != nil {
+		t.Fatalf("parseJudgeScores returned error: %v", err)
+	}
+	if len(scores) != 2 || scores[0].lane != 2 || scores[0].rationale != "best" || scores[1].lane != 0 {
+		t.Fatalf("expected scores mapped to lanes and sorted best first, got %+v", scores)
+	}
+	if _, err := parseJudgeScores(`{"scores":[]}`, candidates); err == nil {
+		t.Fatal("expected an error without usable scores")
+	}
+}
+
+func TestJudgeRound(t *testing.T) {
+	var prompt string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Messages []chatMessage `json:"messages"`
+			Format   any           `json:"format"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		if body.Format == nil {
+			t.Error("expected the judge request to carry a schema")
+		}
+		prompt = body.Messages[len(body.Messages)-1].Content
+		reply, _ := json.Marshal(`{"scores":[{"answer":1,"score":4,"rationale":"wrong"},{"answer":2,"score":9,"rationale":"right"}]}`)
+		fmt.Fprintf(w, `{"message":{"content":%s},"done":true}`+"\n", reply)
+	}))
+	defer srv.Close()
+
+	cfg := &Config{
+		Hosts: []Host{{Name: "A", URL: "http://a", Models: []string{"m"}}, {Name: "B", URL: "http://b", Models: []string{"m"}}},
+		Judge: &JudgeConfig{Model: "judge", host: Host{Name: "J", URL: srv.URL}},
+	}
+	m := initialMultimodelModel(cfg)
+	m.client = srv.Client()
+	m.state = multimodelViewChat
+	m.width, m.height = 120, 40
+	for i, answer := range []string{"5", "4"} {
+		m.assignments[i].selectedModel = "m"
+		m.assignments[i].isAssigned = true
+		m.columnResponses[i].chatHistory = []chatMessage{{Role: "user", Content: "2+2?"}, {Role: "assistant", Content: answer}}
+	}
+
+	cmd := m.runMultimodelCommand("/judge")
+	if cmd == nil || !m.judging {
+		t.Fatalf("expected a judge request, notice %q", m.notice)
+	}
+	m.Update(cmd())
+	if !strings.Contains(prompt, "Question:\n2+2?") || !strings.Contains(prompt, "Answer 2:\n4") {
+		t.Fatalf("unexpected judge prompt: %q", prompt)
+	}
+	if m.judging || len(m.judgement) != 2 || m.judgement[0].lane != 1 {
+		t.Fatalf("expected lane 1 to win, got %+v", m.judgement)
+	}
+	if view := m.View(); !strings.Contains(view, "#1 [2] B / m: 9.0 – right") {
+		t.Fatalf("expected the ranking in the view, got:\n%s", view)
+	}
+
+	m.Update(cmd())
+	m.runMultimodelCommand("/scoreboard")
+	if view := m.View(); !strings.Contains(view, "B / m: 2/2 wins, average 9.0") || !strings.Contains(view, "A / m: 0/2 wins, average 4.0") {
+		t.Fatalf("expected the scoreboard in the view, got:\n%s", view)
+	}
+}
+
+func TestLoadConfigJudge(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	write := func(judge string) {
+		os.WriteFile(path, []byte(`{"hosts":[{"name":"A","url":"http://a","models":["m"]}],"judge":`+judge+`}`), 0o644)
+	}
+
+	write(`{"host":"A","model":"judge","auto":true}`)
+	cfg, err := loadConfig(path)
+	if err != nil || cfg.Judge.host.URL != "http://a" || !cfg.Judge.Auto {
+		t.Fatalf("expected judge host to be resolved, got %+v, err=%v", cfg, err)
+	}
+	write(`{"host":"missing","model":"judge"}`)
+	if _, err := loadConfig(path); err == nil || !strings.Contains(err.Error(), "judge") {
+		t.Fatalf("expected an error for an unknown judge host, got %v", err)
+	}
+}
+
+func TestJudgeIgnoresStaleVerdictsAndOlderAnswers(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reply, _ := json.Marshal(`{"scores":[{"answer":1,"score":4,"rationale":"ok"},{"answer":2,"score":9,"rationale":"good"}]}`)
+		fmt.Fprintf(w, `{"message":{"content":%s},"done":true}`+"\n", reply)
+	}))
+	defer srv.Close()
+
+	cfg := &Config{
+		Hosts: []Host{
+			{Name: "A", URL: "http://a", Models: []string{"m"}},
+			{Name: "B", URL: "http://b", Models: []string{"m"}},
+			{Name: "C", URL: "http://c", Models: []string{"m"}},
+		},
+		Judge: &JudgeConfig{Model: "judge", host: Host{Name: "J", URL: srv.URL}},
+	}
+	m := initialMultimodelModel(cfg)
+	m.client = srv.Client()
+	m.state = multimodelViewChat
+	m.turn = 2
+	for i := range m.assignments {
+		m.assignments[i].selectedModel = "m"
+		m.assignments[i].isAssigned = true
+		m.columnResponses[i].turn = 2
+		m.columnResponses[i].chatHistory = []chatMessage{{Role: "user", Content: "2+2?"}, {Role: "assistant", Content: "4"}}
+	}
+	// C last answered an earlier prompt, as after an @1,2 round.
+	m.columnResponses[2].turn = 1
+	m.columnResponses[2].chatHistory[0].Content = "3+3?"
+
+	cmd := m.startJudging()
+	if cmd == nil {
+		t.Fatalf("expected a judge request, notice %q", m.notice)
+	}
+	msg := cmd().(judgeResultMsg)
+	for _, s := range msg.scores {
+		if s.lane == 2 {
+			t.Fatalf("expected the column without the latest prompt to be left out, got %+v", msg.scores)
+		}
+	}
+
+	// A new round started before the verdict arrived.
+	m.turn = 3
+	m.Update(msg)
+	if m.judging || m.judgement != nil || m.scoreboard != nil {
+		t.Fatalf("expected the late verdict to be dropped, got %+v", m.judgement)
+	}
+
+	// Lanes were reindexed while the judge was busy.
+	m.turn = 2
+	cmd = m.startJudging()
+	msg = cmd().(judgeResultMsg)
+	m.addLane(0)
+	m.Update(msg)
+	if m.judging || m.judgement != nil {
+		t.Fatalf("expected the verdict for the old lane layout to be dropped, got %+v", m.judgement)
+	}
+
+	// Only the latest request's answer counts.
+	m = initialMultimodelModel(cfg)
+	m.client = srv.Client()
+	for i := range m.assignments {
+		m.assignments[i].selectedModel = "m"
+		m.assignments[i].isAssigned = true
+		m.columnResponses[i].chatHistory = []chatMessage{{Role: "user", Content: "2+2?"}, {Role: "assistant", Content: "4"}}
+	}
+	first := m.startJudging()
+	second := m.startJudging()
+	m.Update(first())
+	if !m.judging || m.judgement != nil {
+		t.Fatal("expected the superseded verdict to be ignored")
+	}
+	m.Update(second())
+	if m.judging || len(m.judgement) != 2 {
+		t.Fatalf("expected the latest verdict to be recorded, got %+v", m.judgement)
+	}
+}
@@ -0,0 +1,226 @@
+// cli/lanes.go
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// laneEditField identifies the lane setting being edited in the assignment view.
+type laneEditField int
+
+const (
+	// laneEditNone means no lane setting is being edited
+	laneEditNone laneEditField = iota
+	// laneEditSystemPrompt edits the lane's system prompt
+	laneEditSystemPrompt
+	// laneEditParameters edits the lane's generation parameters
+	laneEditParameters
+	// laneEditPresetName names a preset to save the current lanes as
+	laneEditPresetName
+)
+
+// addLane inserts a copy of the lane at index directly after it, so another
+// model, system prompt or parameter set can be compared on the same host.
+// The new lane starts without a model assigned.
+func (m *multimodelModel) addLane(index int) {
+	lane := m.assignments[index]
+	lane.selectedModel = ""
+	lane.isAssigned = false
+	lane.loadState = laneNotLoaded
+	lane.loadErr = nil
+
+	m.assignments = append(m.assignments[:index+1], append([]hostModelAssignment{lane}, m.assignments[index+1:]...)...)
+	m.columnResponses = append(m.columnResponses[:index+1], append([]multimodelColumnResponse{newColumnResponse(index + 1)}, m.columnResponses[index+1:]...)...)
+	m.reindexColumns()
+}
+
+// deleteLane removes the lane at index. The last remaining lane of a host
+// cannot be removed, so every configured host stays selectable.
+func (m *multimodelModel) deleteLane(index int) bool {
+	if m.lanesForHost(m.assignments[index].host.URL) < 2 {
+		return false
+	}
+	m.assignments = append(m.assignments[:index], m.assignments[index+1:]...)
+	m.columnResponses = append(m.columnResponses[:index], m.columnResponses[index+1:]...)
+	m.reindexColumns()
+	if m.selectedHostIndex >= len(m.assignments) {
+		m.selectedHostIndex = len(m.assignments) - 1
+	}
+	return true
+}
+
+// lanesBusy reports whether replies or a verdict are still on the way. Their
+// messages address lanes by index, so lanes cannot be added or removed until
+// they arrive; the notice says so.
+func (m *multimodelModel) lanesBusy() bool {
+	if !m.isLoading && !m.judging {
+		return false
+	}
+	m.notice = "Wait for the current replies to finish before adding or removing lanes."
+	return true
+}
+
+// reindexColumns keeps each column's lane index in step with its position
+// after lanes were added or removed. Comparisons refer to lanes by index, so
+// they are dropped.
+func (m *multimodelModel) reindexColumns() {
+	for i := range m.columnResponses {
+		m.columnResponses[i].hostIndex = i
+	}
+	m.laneLayout++
+	m.diff = nil
+	m.similarities = nil
+	m.judgement = nil
+	m.votes = nil
+	m.targets = nil
+}
+
+// lanesForHost counts the lanes that target the host at url.
+func (m *multimodelModel) lanesForHost(url string) int {
+	n := 0
+	for _, a := range m.assignments {
+		if a.host.URL == url {
+			n++
+		}
+	}
+	return n
+}
+
+// laneName labels the lane at index for headers: the host name, followed by
+// the lane's position on that host when the host has more than one lane.
+func (m *multimodelModel) laneName(index int) string {
+	host := m.assignments[index].host
+	if m.lanesForHost(host.URL) < 2 {
+		return host.Name
+	}
+	n := 0
+	for i := 0; i <= index; i++ {
+		if m.assignments[i].host.URL == host.URL {
+			n++
+		}
+	}
+	return fmt.Sprintf("%s #%d", host.Name, n)
+}
+
+// laneGroups returns the indices of the targeted lanes grouped into the
+// sequences that are sent one after another. Every lane is its own group
+// unless serializePerHost is set, in which case lanes sharing a host form one
+// group so the host handles a single request at a time.
+func (m *multimodelModel) laneGroups() [][]int {
+	return m.groupLanes(m.roundColumns())
+}
+
+// groupLanes splits lanes into the sequences that run one after another, as
+// described for laneGroups.
+func (m *multimodelModel) groupLanes(lanes []int) [][]int {
+	var groups [][]int
+	byHost := map[string]int{}
+	for _, i := range lanes {
+		a := m.assignments[i]
+		if m.serializePerHost {
+			if g, ok := byHost[a.host.URL]; ok {
+				groups[g] = append(groups[g], i)
+				continue
+			}
+			byHost[a.host.URL] = len(groups)
+		}
+		groups = append(groups, []int{i})
+	}
+	return groups
+}
+
+// loadStatus describes the preload state of the lane at index, or returns
+// "" when its model has not been loaded.
+func (m *multimodelModel) loadStatus(index int) string {
+	lane := m.assignments[index]
+	switch lane.loadState {
+	case laneLoading:
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("205")).Render("loading...")
+	case laneLoaded:
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("2")).Render(fmt.Sprintf("✓ loaded in %.1fs", lane.loadTime.Seconds()))
+	case laneLoadFailed:
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("9")).Render("✗ " + truncate(lane.loadErr.Error(), 60))
+	}
+	return ""
+}
+
+// formatParams renders the set fields of p as space-separated key=value
+// pairs, the same form accepted by parseParams.
+func formatParams(p Parameters) string {
+	var fields map[string]any
+	b, _ := json.Marshal(p)
+	json.Unmarshal(b, &fields)
+
+	pairs := make([]string, 0, len(fields))
+	for k, v := range fields {
+		pairs = append(pairs, fmt.Sprintf("%s=%v", k, v))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, " ")
+}
+
+// startLaneEdit opens the input for field of the selected lane, prefilled
+// with its current value.
+func (m *multimodelModel) startLaneEdit(field laneEditField) {
+	lane := m.assignments[m.selectedHostIndex]
+	m.laneEdit = field
+	m.notice = ""
+	switch field {
+	case laneEditSystemPrompt:
+		m.laneInput.Prompt = "System prompt: "
+		m.laneInput.SetValue(lane.host.SystemPrompt)
+	case laneEditParameters:
+		m.laneInput.Prompt = "Parameters (key=value ...): "
+		m.laneInput.SetValue(formatParams(lane.host.Parameters))
+	case laneEditPresetName:
+		m.laneInput.Prompt = "Save preset as: "
+		m.laneInput.SetValue("")
+	}
+	m.laneInput.CursorEnd()
+	m.laneInput.Focus()
+}
+
+// applyLaneEdit stores the edited value in the selected lane, or saves the
+// lanes as a preset. Invalid input keeps the input open.
+func (m *multimodelModel) applyLaneEdit() {
+	lane := &m.assignments[m.selectedHostIndex]
+	value := strings.TrimSpace(m.laneInput.Value())
+	switch m.laneEdit {
+	case laneEditSystemPrompt:
+		lane.host.SystemPrompt = value
+	case laneEditParameters:
+		params, err := parseParams(Parameters{}, strings.Fields(value))
+		if err != nil {
+			m.notice = err.Error()
+			return
+		}
+		lane.host.Parameters = params
+	case laneEditPresetName:
+		if err := m.savePreset(value); err != nil {
+			m.notice = err.Error()
+			return
+		}
+		m.laneEdit = laneEditNone
+		m.notice = fmt.Sprintf("Saved preset %s.", value)
+		m.laneInput.Blur()
+		return
+	}
+	m.laneEdit = laneEditNone
+	m.notice = ""
+	m.laneInput.Blur()
+}
+
+// truncate shortens s to at most n runes, marking the cut with an ellipsis.
+// Newlines are flattened so the result fits on one line.
+func truncate(s string, n int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if r := []rune(s); len(r) > n {
+		return string(r[:n-1]) + "…"
+	}
+	return s
+}
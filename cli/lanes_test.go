@@ -0,0 +1,113 @@
+// cli/lanes_test.go
+package cli
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestMultimodelLanes(t *testing.T) {
+	cfg := &Config{Hosts: []Host{
+		{Name: "Box", URL: "http://box", Models: []string{"a", "b"}},
+		{Name: "Other", URL: "http://other", Models: []string{"c"}},
+	}}
+	m := initialMultimodelModel(cfg)
+	m.width, m.height = 120, 40
+	press := func(keys ...string) {
+		for _, k := range keys {
+			var msg tea.KeyMsg
+			switch k {
+			case "enter":
+				msg = tea.KeyMsg{Type: tea.KeyEnter}
+			case "ctrl+u":
+				msg = tea.KeyMsg{Type: tea.KeyCtrlU}
+			default:
+				msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+			}
+			m2, _ := m.updateAssignment(msg)
+			m = m2.(*multimodelModel)
+		}
+	}
+
+	// Add two more lanes on the first host.
+	press("a", "a")
+	if len(m.assignments) != 4 || len(m.columnResponses) != 4 {
+		t.Fatalf("expected 4 lanes, got %d assignments and %d columns", len(m.assignments), len(m.columnResponses))
+	}
+	if m.laneName(2) != "Box #3" || m.laneName(3) != "Other" {
+		t.Fatalf("unexpected lane names %q, %q", m.laneName(2), m.laneName(3))
+	}
+
+	// Give the selected lane its own system prompt and parameters.
+	press("s", "ctrl+u", "be terse", "enter", "p", "ctrl+u", "temperature=0.1", "enter")
+	lane := m.assignments[m.selectedHostIndex].host
+	if lane.SystemPrompt != "be terse" || lane.Parameters.Temperature == nil || *lane.Parameters.Temperature != 0.1 {
+		t.Fatalf("expected lane settings to be updated, got %+v", lane)
+	}
+	if m.assignments[0].host.SystemPrompt != "" || m.assignments[0].host.Parameters.Temperature != nil {
+		t.Fatalf("expected other lanes on the host to be unchanged")
+	}
+	if view := m.View(); !strings.Contains(view, "system: be terse") || !strings.Contains(view, "temperature=0.1") {
+		t.Fatalf("expected lane settings in assignment view, got:\n%s", view)
+	}
+
+	// Invalid parameters keep the editor open.
+	press("p", "ctrl+u", "bogus=1", "enter")
+	if m.laneEdit != laneEditParameters || m.notice == "" {
+		t.Fatalf("expected invalid parameters to be rejected")
+	}
+	m.laneEdit = laneEditNone
+
+	for i := range m.assignments {
+		m.assignments[i].selectedModel = "a"
+		m.assignments[i].isAssigned = true
+	}
+	if got := m.laneGroups(); !reflect.DeepEqual(got, [][]int{{0}, {1}, {2}, {3}}) {
+		t.Fatalf("expected concurrent lanes, got %v", got)
+	}
+	press("x")
+	if got := m.laneGroups(); !reflect.DeepEqual(got, [][]int{{0, 1, 2}, {3}}) {
+		t.Fatalf("expected lanes serialized per host, got %v", got)
+	}
+
+	// The last lane of a host cannot be deleted.
+	m.selectedHostIndex = 3
+	press("d")
+	if len(m.assignments) != 4 {
+		t.Fatalf("expected the only lane of a host to be kept")
+	}
+	m.selectedHostIndex = 1
+	press("d")
+	if len(m.assignments) != 3 || m.columnResponses[2].hostIndex != 2 {
+		t.Fatalf("expected lane to be deleted and columns reindexed")
+	}
+}
+
+func TestLanesFixedWhileBusy(t *testing.T) {
+	cfg := &Config{Hosts: []Host{{Name: "Box", URL: "http://box", Models: []string{"a", "b"}}}}
+	m := initialMultimodelModel(cfg)
+	press := func(k string) {
+		m2, _ := m.updateAssignment(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)})
+		m = m2.(*multimodelModel)
+	}
+
+	press("a")
+	for _, busy := range []func(){func() { m.isLoading = true }, func() { m.judging = true }} {
+		m.isLoading, m.judging = false, false
+		busy()
+		press("a")
+		press("d")
+		if len(m.assignments) != 2 || !strings.Contains(m.notice, "Wait for the current replies") {
+			t.Fatalf("expected lanes to stay fixed while busy, got %d lanes, notice %q", len(m.assignments), m.notice)
+		}
+	}
+
+	m.isLoading, m.judging = false, false
+	press("d")
+	if len(m.assignments) != 1 {
+		t.Fatalf("expected the lane to be removed once idle, got %d", len(m.assignments))
+	}
+}
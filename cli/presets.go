@@ -0,0 +1,269 @@
+// cli/presets.go
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// PresetLane is one lane of a multimodel preset.
+type PresetLane struct {
+	// Host is the name or URL of a configured host.
+	Host string `json:"host"`
+	// Model is the model assigned to the lane.
+	Model string `json:"model"`
+	// SystemPrompt overrides the host's system prompt when set.
+	SystemPrompt string `json:"systemprompt,omitempty"`
+	// Parameters override the host's generation parameters when set.
+	Parameters *Parameters `json:"parameters,omitempty"`
+}
+
+// validatePresets checks that every preset lane names a configured host and
+// a model.
+func validatePresets(cfg *Config) error {
+	for name, lanes := range cfg.Presets {
+		if len(lanes) == 0 {
+			return fmt.Errorf("preset %s: no lanes", name)
+		}
+		for _, lane := range lanes {
+			if _, err := findHost(cfg, lane.Host); err != nil {
+				return fmt.Errorf("preset %s: %w", name, err)
+			}
+			if lane.Model == "" {
+				return fmt.Errorf("preset %s: model is required", name)
+			}
+		}
+	}
+	return nil
+}
+
+// presetNames returns the configured preset names in alphabetical order.
+func (cfg *Config) presetNames() []string {
+	names := make([]string, 0, len(cfg.Presets))
+	for name := range cfg.Presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// applyPreset replaces the lanes with those of the named preset. Lanes are
+// grouped by host in config order, and hosts the preset does not use keep a
+// single unassigned lane.
+func (m *multimodelModel) applyPreset(name string) error {
+	preset, ok := m.config.Presets[name]
+	if !ok {
+		return fmt.Errorf("preset %q not found in config", name)
+	}
+
+	var assignments []hostModelAssignment
+	for _, host := range m.config.Hosts {
+		used := false
+		for _, lane := range preset {
+			h, err := findHost(m.config, lane.Host)
+			if err != nil {
+				return fmt.Errorf("preset %s: %w", name, err)
+			}
+			if h.URL != host.URL {
+				continue
+			}
+			used = true
+			a := hostModelAssignment{host: host, models: host.Models, selectedModel: lane.Model, isAssigned: true}
+			if lane.SystemPrompt != "" {
+				a.host.SystemPrompt = lane.SystemPrompt
+			}
+			if lane.Parameters != nil {
+				a.host.Parameters = *lane.Parameters
+			}
+			assignments = append(assignments, a)
+		}
+		if !used {
+			assignments = append(assignments, hostModelAssignment{host: host, models: host.Models})
+		}
+	}
+
+	m.assignments = assignments
+	m.columnResponses = make([]multimodelColumnResponse, len(assignments))
+	for i := range m.columnResponses {
+		m.columnResponses[i] = newColumnResponse(i)
+	}
+	m.reindexColumns()
+	m.selectedHostIndex = 0
+	m.focusedColumn = 0
+	m.columnOffset = 0
+	return nil
+}
+
+// savePreset stores the assigned lanes as the named preset, both in the
+// loaded config and in the config file.
+func (m *multimodelModel) savePreset(name string) error {
+	if name == "" {
+		return errors.New("preset name is required")
+	}
+	var lanes []PresetLane
+	for _, i := range m.assignedColumns() {
+		a := m.assignments[i]
+		lane := PresetLane{Host: a.host.Name, Model: a.selectedModel, SystemPrompt: a.host.SystemPrompt}
+		if formatParams(a.host.Parameters) != "" {
+			params := a.host.Parameters
+			lane.Parameters = &params
+		}
+		lanes = append(lanes, lane)
+	}
+	if len(lanes) == 0 {
+		return errors.New("assign at least one model before saving a preset")
+	}
+
+	if m.config.path != "" {
+		if err := writePreset(m.config.path, name, lanes); err != nil {
+			return err
+		}
+	}
+	if m.config.Presets == nil {
+		m.config.Presets = map[string][]PresetLane{}
+	}
+	m.config.Presets[name] = lanes
+	return nil
+}
+
+// writePreset adds or replaces a preset in the config file at path. Only the
+// value of the "presets" key is rewritten, or the key is added at the end;
+// the rest of the file keeps its key order and formatting.
+func writePreset(path, name string, lanes []PresetLane) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("could not read config file: %w", err)
+	}
+	start, end, err := findTopLevelKey(b, "presets")
+	if err != nil {
+		return fmt.Errorf("could not parse config JSON: %w", err)
+	}
+
+	presets := map[string]json.RawMessage{}
+	if start >= 0 {
+		if err := json.Unmarshal(b[start:end], &presets); err != nil {
+			return fmt.Errorf("could not parse presets: %w", err)
+		}
+	}
+	presets[name], _ = json.Marshal(lanes)
+
+	var out []byte
+	if start >= 0 {
+		// Indent the value like the line its key is on.
+		line := b[bytes.LastIndexByte(b[:start], '\n')+1:]
+		indent := line[:len(line)-len(bytes.TrimLeft(line, " \t"))]
+		value, _ := json.MarshalIndent(presets, string(indent), "  ")
+		out = append(append(append(out, b[:start]...), value...), b[end:]...)
+	} else {
+		value, _ := json.MarshalIndent(presets, "  ", "  ")
+		closing := bytes.LastIndexByte(b, '}')
+		body := bytes.TrimRight(b[:closing], " \t\r\n")
+		sep := ",\n"
+		if bytes.HasSuffix(body, []byte("{")) {
+			sep = "\n"
+		}
+		out = append(append(out, body...), sep+"  \"presets\": "...)
+		out = append(append(out, value...), '\n')
+		out = append(out, b[closing:]...)
+	}
+	if err := os.WriteFile(path, out, 0o644); err != nil {
+		return fmt.Errorf("could not write config file: %w", err)
+	}
+	return nil
+}
+
+// findTopLevelKey returns the byte range of the value of key in the JSON
+// object b, or -1, -1 when the object has no such key.
+func findTopLevelKey(b []byte, key string) (start, end int, err error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return 0, 0, errors.New("config is not a JSON object")
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return 0, 0, err
+		}
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return 0, 0, err
+		}
+		if tok == key {
+			end := int(dec.InputOffset())
+			return end - len(value), end, nil
+		}
+	}
+	return -1, -1, nil
+}
+
+// updatePresets handles keys in the preset picker. The first entry starts
+// from manual assignment; the others apply a preset and start the chat.
+func (m *multimodelModel) updatePresets(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	names := m.config.presetNames()
+	switch keyMsg.String() {
+	case "up", "k":
+		if m.presetIndex > 0 {
+			m.presetIndex--
+		}
+	case "down", "j":
+		if m.presetIndex < len(names) {
+			m.presetIndex++
+		}
+	case "enter":
+		if m.presetIndex == 0 {
+			m.state = multimodelViewAssignment
+			return m, nil
+		}
+		if err := m.applyPreset(names[m.presetIndex-1]); err != nil {
+			m.notice = err.Error()
+			return m, nil
+		}
+		return m, m.startChat()
+	}
+	return m, nil
+}
+
+// presetView renders the preset picker.
+func (m *multimodelModel) presetView() string {
+	var builder strings.Builder
+
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("5"))
+	builder.WriteString(titleStyle.Render("Multimodel Mode - Choose a Preset") + "\n\n")
+
+	entries := []string{"Assign models manually"}
+	for _, name := range m.config.presetNames() {
+		models := make([]string, 0, len(m.config.Presets[name]))
+		for _, lane := range m.config.Presets[name] {
+			models = append(models, lane.Model)
+		}
+		entries = append(entries, lipgloss.NewStyle().Bold(true).Render(name)+
+			lipgloss.NewStyle().Faint(true).Render("  "+strings.Join(models, ", ")))
+	}
+	for i, entry := range entries {
+		if i == m.presetIndex {
+			builder.WriteString("> ")
+		} else {
+			builder.WriteString("  ")
+		}
+		builder.WriteString(entry + "\n")
+	}
+
+	builder.WriteString("\n")
+	if m.notice != "" {
+		builder.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("9")).Render(m.notice) + "\n")
+	}
+	builder.WriteString(lipgloss.NewStyle().Faint(true).Render("↑/↓: Navigate  Enter: Select"))
+	return lipgloss.NewStyle().Margin(1, 2).Render(builder.String())
+}
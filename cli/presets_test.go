@@ -0,0 +1,135 @@
+// cli/presets_test.go
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestMultimodelPresets(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	config := `{
+  "hosts": [
+    {"name": "A", "url": "http://a", "models": ["m1", "m2"], "systemprompt": "default"},
+    {"name": "B", "url": "http://b", "models": ["m3"]}
+  ],
+  "future_setting": {"keep": true},
+  "presets": {
+    "shootout": [
+      {"host": "A", "model": "m1"},
+      {"host": "A", "model": "m2", "systemprompt": "be terse", "parameters": {"temperature": 0.2}}
+    ]
+  }
+}`
+	if err := os.WriteFile(path, []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := loadConfig(path)
+	if err != nil {
+		t.Fatalf("loadConfig: %v", err)
+	}
+
+	m := initialMultimodelModel(cfg)
+	m.width, m.height = 120, 30
+	if m.state != multimodelViewPresets || !strings.Contains(m.View(), "shootout") {
+		t.Fatalf("expected the preset picker, got:\n%s", m.View())
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.state != multimodelViewLoadingChat {
+		t.Fatalf("expected choosing a preset to start the chat, got state %d", m.state)
+	}
+	if len(m.assignments) != 3 || len(m.columnResponses) != 3 {
+		t.Fatalf("expected two preset lanes plus an unassigned lane for B, got %d", len(m.assignments))
+	}
+	if got := m.assignedColumns(); len(got) != 2 {
+		t.Fatalf("expected two assigned lanes, got %v", got)
+	}
+	lane := m.assignments[1].host
+	if m.assignments[0].host.SystemPrompt != "default" || lane.SystemPrompt != "be terse" || *lane.Parameters.Temperature != 0.2 {
+		t.Fatalf("expected preset overrides on the second lane only, got %+v", m.assignments)
+	}
+
+	// Save a new preset from the assignment view.
+	m.state = multimodelViewAssignment
+	m.assignments[2].selectedModel = "m3"
+	m.assignments[2].isAssigned = true
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("w")})
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("trio")})
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.notice != "Saved preset trio." {
+		t.Fatalf("expected the preset to be saved, got %q", m.notice)
+	}
+
+	b, _ := os.ReadFile(path)
+	var file map[string]json.RawMessage
+	if err := json.Unmarshal(b, &file); err != nil {
+		t.Fatalf("config is no longer valid JSON: %v", err)
+	}
+	if _, ok := file["future_setting"]; !ok {
+		t.Fatalf("expected unknown settings to be preserved, got:\n%s", b)
+	}
+	saved, err := loadConfig(path)
+	if err != nil {
+		t.Fatalf("loadConfig after save: %v", err)
+	}
+	if len(saved.Presets) != 2 || len(saved.Presets["trio"]) != 3 || saved.Presets["trio"][2].Model != "m3" {
+		t.Fatalf("expected both presets in the saved config, got %+v", saved.Presets)
+	}
+	if saved.Presets["trio"][1].Parameters == nil || saved.Presets["trio"][0].Parameters != nil {
+		t.Fatalf("expected only set parameters to be saved, got %+v", saved.Presets["trio"])
+	}
+}
+
+func TestLoadConfigRejectsInvalidPresets(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	config := `{"hosts": [{"name": "A", "url": "http://a"}], "presets": {"bad": [{"host": "Z", "model": "m"}]}}`
+	if err := os.WriteFile(path, []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadConfig(path); err == nil || !strings.Contains(err.Error(), "preset bad") {
+		t.Fatalf("expected an unknown preset host to be rejected, got %v", err)
+	}
+}
+
+func TestWritePresetKeepsFormatting(t *testing.T) {
+	dir := t.TempDir()
+	lanes := []PresetLane{{Host: "A", Model: "m1"}}
+
+	for name, config := range map[string]string{
+		"existing": "{\n    \"zeta\": 1,\n    \"hosts\": [ {\"name\": \"A\"} ],\n    \"presets\": {\"old\": [{\"host\": \"A\", \"model\": \"m0\"}]},\n    \"alpha\": true\n}\n",
+		"missing":  "{\n  \"zeta\": 1,\n  \"hosts\": [ {\"name\": \"A\"} ]\n}\n",
+	} {
+		path := filepath.Join(dir, name+".json")
+		os.WriteFile(path, []byte(config), 0o644)
+		if err := writePreset(path, "new", lanes); err != nil {
+			t.Fatalf("%s: writePreset: %v", name, err)
+		}
+		b, _ := os.ReadFile(path)
+		got := string(b)
+
+		// Everything before the presets value is untouched.
+		keep := config[:strings.Index(config, "\"hosts\": [ {\"name\": \"A\"} ]")]
+		if !strings.HasPrefix(got, keep) || !strings.Contains(got, "\"hosts\": [ {\"name\": \"A\"} ]") {
+			t.Errorf("%s: expected the other keys to keep their order and formatting, got:\n%s", name, got)
+		}
+		if name == "existing" && !strings.HasSuffix(got, "},\n    \"alpha\": true\n}\n") {
+			t.Errorf("%s: expected keys after presets to stay in place, got:\n%s", name, got)
+		}
+
+		var file struct {
+			Presets map[string][]PresetLane `json:"presets"`
+		}
+		if err := json.Unmarshal(b, &file); err != nil {
+			t.Fatalf("%s: config is no longer valid JSON: %v\n%s", name, err, got)
+		}
+		if file.Presets["new"][0].Model != "m1" || (name == "existing" && file.Presets["old"][0].Model != "m0") {
+			t.Errorf("%s: unexpected presets %+v", name, file.Presets)
+		}
+	}
+}
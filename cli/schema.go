@@ -0,0 +1,244 @@
+// cli/schema.go
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// loadSchema resolves a schema setting from the configuration. raw is either
+// an inline JSON Schema object or a string holding the path of a schema file.
+// It returns nil when raw is empty.
+func loadSchema(raw json.RawMessage) (map[string]any, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+
+	var path string
+	if err := json.Unmarshal(raw, &path); err == nil {
+		return loadSchemaFile(path)
+	}
+
+	var schema map[string]any
+	if err := json.Unmarshal(raw, &schema); err != nil {
+		return nil, fmt.Errorf("schema must be a JSON object or a file path: %w", err)
+	}
+	return schema, nil
+}
+
+// loadSchemaFile reads a JSON Schema object from path.
+func loadSchemaFile(path string) (map[string]any, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read schema file: %w", err)
+	}
+	var schema map[string]any
+	if err := json.Unmarshal(b, &schema); err != nil {
+		return nil, fmt.Errorf("could not parse schema file %s: %w", path, err)
+	}
+	return schema, nil
+}
+
+// schemaResult is the outcome of validating a completed response.
+type schemaResult struct {
+	// checked is true once a response has been validated.
+	checked bool
+	// errs lists the validation failures; empty means the response passed.
+	errs []string
+}
+
+// checkResponse parses content as JSON and validates it against schema.
+func checkResponse(schema map[string]any, content string) schemaResult {
+	var value any
+	if err := json.Unmarshal([]byte(content), &value); err != nil {
+		return schemaResult{checked: true, errs: []string{"response is not valid JSON"}}
+	}
+	return schemaResult{checked: true, errs: validateSchema(schema, value, "$")}
+}
+
+// label renders the result for a header: a green pass or a red failure count.
+func (r schemaResult) label() string {
+	if !r.checked {
+		return lipgloss.NewStyle().Faint(true).Render("Schema: pending")
+	}
+	if len(r.errs) == 0 {
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("40")).Render("Schema: pass")
+	}
+	return lipgloss.NewStyle().Foreground(lipgloss.Color("9")).Render(fmt.Sprintf("Schema: fail (%s)", r.errs[0]))
+}
+
+// shortLabel renders the result compactly for multimodel column headers.
+func (r schemaResult) shortLabel() string {
+	switch {
+	case !r.checked:
+		return ""
+	case len(r.errs) == 0:
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("40")).Render("✓ schema")
+	default:
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("9")).Render("✗ schema")
+	}
+}
+
+// validateSchema checks value against a subset of JSON Schema: type, enum,
+// const, properties, required, additionalProperties, items, minItems,
+// maxItems, minLength, maxLength, pattern, minimum and maximum. It returns a
+// description of each violation found, prefixed with its JSON path.
+func validateSchema(schema map[string]any, value any, path string) []string {
+	var errs []string
+	fail := func(format string, args ...any) {
+		errs = append(errs, path+": "+fmt.Sprintf(format, args...))
+	}
+
+	if t, ok := schema["type"]; ok && !matchesType(t, value) {
+		fail("expected %v", t)
+		return errs
+	}
+	if enum, ok := schema["enum"].([]any); ok {
+		found := false
+		for _, e := range enum {
+			if jsonEqual(e, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			fail("value not in enum")
+		}
+	}
+	if c, ok := schema["const"]; ok && !jsonEqual(c, value) {
+		fail("value does not match const")
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		props, _ := schema["properties"].(map[string]any)
+		if required, ok := schema["required"].([]any); ok {
+			for _, r := range required {
+				if name, ok := r.(string); ok {
+					if _, present := v[name]; !present {
+						fail("missing required property %q", name)
+					}
+				}
+			}
+		}
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if sub, ok := props[k].(map[string]any); ok {
+				errs = append(errs, validateSchema(sub, v[k], path+"."+k)...)
+			} else if allowed, ok := schema["additionalProperties"].(bool); ok && !allowed {
+				fail("unexpected property %q", k)
+			}
+		}
+
+	case []any:
+		if n, ok := schemaNumber(schema, "minItems"); ok && float64(len(v)) < n {
+			fail("expected at least %v items", n)
+		}
+		if n, ok := schemaNumber(schema, "maxItems"); ok && float64(len(v)) > n {
+			fail("expected at most %v items", n)
+		}
+		if items, ok := schema["items"].(map[string]any); ok {
+			for i, item := range v {
+				errs = append(errs, validateSchema(items, item, fmt.Sprintf("%s[%d]", path, i))...)
+			}
+		}
+
+	case string:
+		if n, ok := schemaNumber(schema, "minLength"); ok && float64(len([]rune(v))) < n {
+			fail("expected at least %v characters", n)
+		}
+		if n, ok := schemaNumber(schema, "maxLength"); ok && float64(len([]rune(v))) > n {
+			fail("expected at most %v characters", n)
+		}
+		if pattern, ok := schema["pattern"].(string); ok {
+			if re, err := regexp.Compile(pattern); err == nil && !re.MatchString(v) {
+				fail("does not match pattern %q", pattern)
+			}
+		}
+
+	case float64:
+		if n, ok := schemaNumber(schema, "minimum"); ok && v < n {
+			fail("expected a value >= %v", n)
+		}
+		if n, ok := schemaNumber(schema, "maximum"); ok && v > n {
+			fail("expected a value <= %v", n)
+		}
+	}
+	return errs
+}
+
+// matchesType reports whether value has the JSON type t, which may be a
+// single type name or a list of names.
+func matchesType(t any, value any) bool {
+	if list, ok := t.([]any); ok {
+		for _, item := range list {
+			if matchesType(item, value) {
+				return true
+			}
+		}
+		return false
+	}
+	switch t {
+	case "object":
+		_, ok := value.(map[string]any)
+		return ok
+	case "array":
+		_, ok := value.([]any)
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		f, ok := value.(float64)
+		return ok && f == math.Trunc(f)
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "null":
+		return value == nil
+	}
+	return true
+}
+
+// schemaNumber returns the numeric keyword key from schema.
+func schemaNumber(schema map[string]any, key string) (float64, bool) {
+	n, ok := schema[key].(float64)
+	return n, ok
+}
+
+// jsonEqual compares two decoded JSON values.
+func jsonEqual(a, b any) bool {
+	ab, _ := json.Marshal(a)
+	bb, _ := json.Marshal(b)
+	return bytes.Equal(ab, bb)
+}
+
+// prettyJSON indents content when it is a JSON object or array. It returns
+// content unchanged otherwise.
+func prettyJSON(content string) string {
+	trimmed := strings.TrimSpace(content)
+	if !strings.HasPrefix(trimmed, "{") && !strings.HasPrefix(trimmed, "[") {
+		return content
+	}
+	var out bytes.Buffer
+	if err := json.Indent(&out, []byte(trimmed), "", "  "); err != nil {
+		return content
+	}
+	return out.String()
+}
@@ -0,0 +1,83 @@
+// cli/schema_test.go
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadSchema(t *testing.T) {
+	inline, err := loadSchema(json.RawMessage(`{"type":"object"}`))
+	if err != nil || inline["type"] != "object" {
+		t.Fatalf("expected inline schema, got %v, err=%v", inline, err)
+	}
+
+	path := filepath.Join(t.TempDir(), "schema.json")
+	if err := os.WriteFile(path, []byte(`{"type":"array"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	raw, _ := json.Marshal(path)
+	fromFile, err := loadSchema(raw)
+	if err != nil || fromFile["type"] != "array" {
+		t.Fatalf("expected schema from file, got %v, err=%v", fromFile, err)
+	}
+
+	if none, err := loadSchema(nil); none != nil || err != nil {
+		t.Fatalf("expected no schema for empty setting, got %v, err=%v", none, err)
+	}
+	if _, err := loadSchema(json.RawMessage(`42`)); err == nil {
+		t.Fatal("expected error for a non-object schema")
+	}
+}
+
+func TestCheckResponse(t *testing.T) {
+	var schema map[string]any
+	json.Unmarshal([]byte(`{
+		"type": "object",
+		"required": ["name", "tags"],
+		"additionalProperties": false,
+		"properties": {
+			"name": {"type": "string", "minLength": 2},
+			"age": {"type": "integer", "minimum": 0},
+			"tags": {"type": "array", "items": {"enum": ["a", "b"]}, "maxItems": 2}
+		}
+	}`), &schema)
+
+	if res := checkResponse(schema, `{"name":"Ada","age":36,"tags":["a"]}`); len(res.errs) != 0 {
+		t.Fatalf("expected valid response to pass, got %v", res.errs)
+	}
+
+	res := checkResponse(schema, `{"name":"A","age":1.5,"tags":["c"],"extra":true}`)
+	want := []string{`$.age: expected integer`, `$: unexpected property "extra"`, `$.name: expected at least 2 characters`, `$.tags[0]: value not in enum`}
+	if strings.Join(res.errs, "|") != strings.Join(want, "|") {
+		t.Fatalf("unexpected validation errors:\n got %v\nwant %v", res.errs, want)
+	}
+
+	if res := checkResponse(schema, "not json"); len(res.errs) != 1 || !res.checked {
+		t.Fatalf("expected invalid JSON to fail, got %+v", res)
+	}
+}
+
+func TestChatRequestSchemaFormat(t *testing.T) {
+	schema := map[string]any{"type": "object"}
+	r := chatRequest{model: "m", json: true, schema: schema}
+	if format, ok := r.payload()["format"].(map[string]any); !ok || format["type"] != "object" {
+		t.Fatalf("expected schema to be sent as format, got %v", r.payload()["format"])
+	}
+	r.schema = nil
+	if r.payload()["format"] != "json" {
+		t.Fatalf("expected json format without a schema")
+	}
+}
+
+func TestPrettyJSON(t *testing.T) {
+	if got := prettyJSON(`{"a":1}`); got != "{\n  \"a\": 1\n}" {
+		t.Errorf("unexpected pretty output: %q", got)
+	}
+	if got := prettyJSON("plain text"); got != "plain text" {
+		t.Errorf("expected plain text unchanged, got %q", got)
+	}
+}
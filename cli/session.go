@@ -0,0 +1,111 @@
+// cli/session.go
+package cli
+
+// sessionNode is a single message in the conversation tree. Each node keeps
+// every alternative continuation as a child and remembers which one is active.
+type sessionNode struct {
+	message  chatMessage
+	parent   *sessionNode
+	children []*sessionNode
+	// active is the index of the child that belongs to the current branch.
+	active int
+}
+
+// sessionTree stores a chat conversation as a tree of messages so that
+// regenerated answers and edited prompts are kept as sibling branches
+// instead of overwriting earlier turns. The active branch is the path from
+// the root following each node's active child.
+type sessionTree struct {
+	root *sessionNode
+}
+
+// newSessionTree returns an empty conversation tree.
+func newSessionTree() *sessionTree {
+	return &sessionTree{root: &sessionNode{}}
+}
+
+// activeChild returns the active child of n, or nil if n is a leaf.
+func (n *sessionNode) activeChild() *sessionNode {
+	if len(n.children) == 0 {
+		return nil
+	}
+	return n.children[n.active]
+}
+
+// nodeAt returns the node holding the message at the given depth of the
+// active branch (0-based), or the root when depth is -1. It returns nil if
+// the branch is shorter than depth.
+func (t *sessionTree) nodeAt(depth int) *sessionNode {
+	n := t.root
+	for i := 0; i <= depth; i++ {
+		n = n.activeChild()
+		if n == nil {
+			return nil
+		}
+	}
+	return n
+}
+
+// path returns the messages on the active branch in order.
+func (t *sessionTree) path() []chatMessage {
+	var msgs []chatMessage
+	for n := t.root.activeChild(); n != nil; n = n.activeChild() {
+		msgs = append(msgs, n.message)
+	}
+	return msgs
+}
+
+// leaf returns the last node of the active branch.
+func (t *sessionTree) leaf() *sessionNode {
+	n := t.root
+	for c := n.activeChild(); c != nil; c = n.activeChild() {
+		n = c
+	}
+	return n
+}
+
+// append adds msg to the end of the active branch.
+func (t *sessionTree) append(msg chatMessage) {
+	addChild(t.leaf(), msg)
+}
+
+// fork adds msg as a new alternative for the message at depth on the active
+// branch and makes it active. Messages after depth on the old branch are kept
+// in the tree but are no longer part of the active path. It reports false if
+// the active branch is shorter than depth.
+func (t *sessionTree) fork(depth int, msg chatMessage) bool {
+	parent := t.nodeAt(depth - 1)
+	if parent == nil {
+		return false
+	}
+	addChild(parent, msg)
+	return true
+}
+
+// siblings returns the position of the active alternative at depth and the
+// total number of alternatives stored for that turn.
+func (t *sessionTree) siblings(depth int) (index, count int) {
+	parent := t.nodeAt(depth - 1)
+	if parent == nil || len(parent.children) == 0 {
+		return 0, 0
+	}
+	return parent.active, len(parent.children)
+}
+
+// switchSibling moves the active branch at depth by delta alternatives,
+// wrapping around. It reports whether the active branch changed.
+func (t *sessionTree) switchSibling(depth, delta int) bool {
+	parent := t.nodeAt(depth - 1)
+	if parent == nil || len(parent.children) < 2 {
+		return false
+	}
+	n := len(parent.children)
+	parent.active = ((parent.active+delta)%n + n) % n
+	return true
+}
+
+// addChild appends msg as a new child of parent and activates it.
+func addChild(parent *sessionNode, msg chatMessage) {
+	parent.children = append(parent.children, &sessionNode{message: msg, parent: parent})
+	parent.active = len(parent.children) - 1
+}
@@ -0,0 +1,176 @@
+// cli/session_test.go
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestSessionTreeForkAndSwitch(t *testing.T) {
+	tree := newSessionTree()
+	tree.append(chatMessage{Role: "user", Content: "q1"})
+	tree.append(chatMessage{Role: "assistant", Content: "a1"})
+
+	// Regenerate the answer as a sibling branch.
+	if !tree.fork(1, chatMessage{Role: "assistant", Content: "a1-retry"}) {
+		t.Fatal("expected fork to succeed")
+	}
+	path := tree.path()
+	if len(path) != 2 || path[1].Content != "a1-retry" {
+		t.Fatalf("expected retried answer on active path, got %v", path)
+	}
+	if index, count := tree.siblings(1); index != 1 || count != 2 {
+		t.Fatalf("expected variant 2 of 2, got %d of %d", index+1, count)
+	}
+
+	// Flip back to the original answer.
+	tree.switchSibling(1, -1)
+	if got := tree.path()[1].Content; got != "a1" {
+		t.Fatalf("expected original answer after switching, got %q", got)
+	}
+
+	// Editing the first user turn starts a new branch from the root.
+	tree.fork(0, chatMessage{Role: "user", Content: "q1-edited"})
+	path = tree.path()
+	if len(path) != 1 || path[0].Content != "q1-edited" {
+		t.Fatalf("expected edited prompt to replace the branch, got %v", path)
+	}
+	tree.switchSibling(0, 1)
+	if path = tree.path(); len(path) != 2 || path[1].Content != "a1" {
+		t.Fatalf("expected original branch to be preserved, got %v", path)
+	}
+
+	if tree.fork(5, chatMessage{Role: "user"}) {
+		t.Fatal("expected fork beyond the branch length to fail")
+	}
+}
+
+func TestSlashCommandsRetryAndEdit(t *testing.T) {
+	cfg := &Config{Hosts: []Host{{Name: "HostA", URL: "http://x", Models: []string{"m1"}}}}
+	m := initialModel(cfg)
+	m.state = viewChat
+	m.program = &tea.Program{}
+
+	m.session.append(chatMessage{Role: "user", Content: "hello"})
+	m.session.append(chatMessage{Role: "assistant", Content: "first"})
+	m.chatHistory = m.session.path()
+
+	// /retry drops the last answer and regenerates it as a new branch.
+	m.textArea.SetValue("/retry")
+	m2, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = m2.(*model)
+	if !m.isLoading || len(m.chatHistory) != 1 || m.forkDepth != 1 {
+		t.Fatalf("expected retry to resend without the last answer; loading=%v history=%v", m.isLoading, m.chatHistory)
+	}
+	m2, _ = m.Update(streamChunkMsg("second"))
+	m = m2.(*model)
+	m2, _ = m.Update(streamEndMsg{meta: LLMResponseMeta{Done: true}})
+	m = m2.(*model)
+	if got := m.chatHistory[1].Content; got != "second" {
+		t.Fatalf("expected regenerated answer, got %q", got)
+	}
+
+	// /prev flips back to the first variant.
+	m.textArea.SetValue("/prev")
+	m2, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = m2.(*model)
+	if got := m.chatHistory[1].Content; got != "first" {
+		t.Fatalf("expected first variant after /prev, got %q", got)
+	}
+
+	// /edit loads the prompt into the input and resends from that turn.
+	m.textArea.SetValue("/edit 1")
+	m2, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = m2.(*model)
+	if m.editDepth != 0 || m.textArea.Value() != "hello" {
+		t.Fatalf("expected editing turn 1; depth=%d value=%q", m.editDepth, m.textArea.Value())
+	}
+	m.textArea.SetValue("hello again")
+	m2, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = m2.(*model)
+	if len(m.chatHistory) != 1 || m.chatHistory[0].Content != "hello again" {
+		t.Fatalf("expected edited prompt to start a new branch, got %v", m.chatHistory)
+	}
+	if _, count := m.session.siblings(0); count != 2 {
+		t.Fatalf("expected two variants of the first turn, got %d", count)
+	}
+}
+
+func TestEditCancelledBySwitchingBranches(t *testing.T) {
+	cfg := &Config{Hosts: []Host{{Name: "HostA", URL: "http://x", Models: []string{"m1"}}}}
+	m := initialModel(cfg)
+	m.state = viewChat
+	m.program = &tea.Program{}
+
+	m.session.append(chatMessage{Role: "user", Content: "q1"})
+	m.session.append(chatMessage{Role: "assistant", Content: "a1"})
+	m.session.append(chatMessage{Role: "user", Content: "q2"})
+	m.session.append(chatMessage{Role: "assistant", Content: "a2"})
+	m.session.fork(0, chatMessage{Role: "user", Content: "other"})
+	m.session.append(chatMessage{Role: "assistant", Content: "b1"})
+	m.chatHistory = m.session.path()
+
+	send := func(input string) {
+		t.Helper()
+		m.textArea.SetValue(input)
+		m2, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		m = m2.(*model)
+	}
+
+	// Start editing on the short branch, then switch to the long one.
+	send("/edit 1")
+	if m.editDepth != 0 {
+		t.Fatalf("expected an edit of turn 1, got depth %d", m.editDepth)
+	}
+	send("/prev")
+	if m.editDepth != -1 || m.textArea.Value() != "" {
+		t.Fatalf("expected switching branches to cancel the edit; depth=%d value=%q", m.editDepth, m.textArea.Value())
+	}
+	if len(m.chatHistory) != 4 {
+		t.Fatalf("expected the long branch, got %v", m.chatHistory)
+	}
+
+	// The next prompt extends the branch instead of forking turn 1.
+	send("q3")
+	if len(m.chatHistory) != 5 || m.chatHistory[3].Content != "a2" || m.chatHistory[4].Content != "q3" {
+		t.Fatalf("expected q3 appended to the long branch, got %v", m.chatHistory)
+	}
+	m2, _ := m.Update(streamEndMsg{meta: LLMResponseMeta{Done: true}})
+	m = m2.(*model)
+
+	// Esc and /edit cancel abandon an edit.
+	send("/edit 2")
+	m2, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = m2.(*model)
+	if m.editDepth != -1 || m.textArea.Value() != "" {
+		t.Fatalf("expected esc to cancel the edit; depth=%d value=%q", m.editDepth, m.textArea.Value())
+	}
+	send("/edit 2")
+	send("/edit cancel")
+	if m.editDepth != -1 || m.notice != "Edit cancelled." {
+		t.Fatalf("expected /edit cancel to cancel the edit; depth=%d notice=%q", m.editDepth, m.notice)
+	}
+}
+
+func TestDoubleSlashSendsMessage(t *testing.T) {
+	cfg := &Config{Hosts: []Host{{Name: "HostA", URL: "http://x", Models: []string{"m1"}}}}
+	m := initialModel(cfg)
+	m.state = viewChat
+	m.program = &tea.Program{}
+
+	m.textArea.SetValue("/usr/bin/env fails")
+	m2, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = m2.(*model)
+	if len(m.chatHistory) != 0 || !strings.Contains(m.notice, "//") {
+		t.Fatalf("expected an unknown command hinting at //; history=%v notice=%q", m.chatHistory, m.notice)
+	}
+
+	m.textArea.SetValue("//usr/bin/env fails")
+	m2, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = m2.(*model)
+	if len(m.chatHistory) != 1 || m.chatHistory[0].Content != "/usr/bin/env fails" || !m.isLoading {
+		t.Fatalf("expected the message sent with one slash, got %v", m.chatHistory)
+	}
+}
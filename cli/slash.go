@@ -0,0 +1,329 @@
+// cli/slash.go
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// slashCommand reports whether input is a chat command. Input starting with
+// "//" is a message that starts with a slash; it is returned with one slash
+// removed.
+func slashCommand(input string) (string, bool) {
+	if strings.HasPrefix(input, "//") {
+		return input[1:], false
+	}
+	return input, strings.HasPrefix(input, "/")
+}
+
+// runSlashCommand executes a chat command typed into the input area, such as
+// "/retry" or "/edit 2". It returns a command to run, if any, and records
+// feedback for the user in m.notice.
+func (m *model) runSlashCommand(input string) tea.Cmd {
+	fields := strings.Fields(input)
+	name, args := fields[0], fields[1:]
+
+	switch name {
+	case "/retry":
+		depth := lastIndexOfRole(m.chatHistory, "assistant")
+		if depth < 0 || depth != len(m.chatHistory)-1 {
+			m.notice = "Nothing to retry yet."
+			return nil
+		}
+		m.forkDepth = depth
+		m.invalidateSummary(depth)
+		m.chatHistory = m.chatHistory[:depth]
+		m.notice = ""
+		return m.sendChat()
+
+	case "/edit":
+		if len(args) > 0 && args[0] == "cancel" {
+			m.cancelEdit()
+			return nil
+		}
+		turn := 0
+		if len(args) > 0 {
+			n, err := strconv.Atoi(args[0])
+			if err != nil || n < 1 {
+				m.notice = fmt.Sprintf("Invalid turn number: %s", args[0])
+				return nil
+			}
+			turn = n
+		}
+		depth := userTurnIndex(m.chatHistory, turn)
+		if depth < 0 {
+			m.notice = "No matching user message to edit."
+			return nil
+		}
+		m.dropEdit()
+		m.editDepth = depth
+		m.textArea.SetValue(m.chatHistory[depth].Content)
+		m.textArea.CursorEnd()
+		images := imageAttachments(m.chatHistory[depth])
+		m.attachments = append(images, m.attachments...)
+		m.editImages = len(images)
+		m.notice = "Editing earlier message; press enter to resend from there, esc to cancel."
+		if len(images) > 0 {
+			m.notice = fmt.Sprintf("Editing earlier message with its %d image(s) attached (/detach drops them); press enter to resend from there, esc to cancel.", len(images))
+		}
+		return nil
+
+	case "/attach":
+		m.notice = queueAttachment(&m.attachments, input)
+		return nil
+
+	case "/detach":
+		m.attachments = nil
+		m.editImages = 0
+		m.notice = "Cleared pending attachments."
+		return nil
+
+	case "/think":
+		think, ok := parseThinkArg(args)
+		if !ok {
+			m.notice = "Usage: /think on|off|default"
+			return nil
+		}
+		m.think = think
+		m.notice = thinkLabel(think)
+		return nil
+
+	case "/schema":
+		schema, notice := parseSchemaArg(input)
+		if notice == "" {
+			m.sessionSchema = schema
+			m.schemaCheck = schemaResult{}
+			notice = "Session schema cleared."
+			if schema != nil {
+				notice = "Session schema set."
+			}
+		}
+		m.notice = notice
+		return nil
+
+	case "/next", "/prev":
+		delta := 1
+		if name == "/prev" {
+			delta = -1
+		}
+		for depth := len(m.chatHistory) - 1; depth >= 0; depth-- {
+			if _, count := m.session.siblings(depth); count > 1 {
+				// An edit in progress points into the old branch.
+				m.dropEdit()
+				m.session.switchSibling(depth, delta)
+				m.invalidateSummary(depth)
+				m.chatHistory = m.session.path()
+				index, count := m.session.siblings(depth)
+				m.notice = fmt.Sprintf("Showing variant %d of %d.", index+1, count)
+				m.viewport.GotoBottom()
+				return nil
+			}
+		}
+		m.notice = "No alternative branches to switch between."
+		return nil
+
+	default:
+		m.notice = fmt.Sprintf("Unknown command: %s (start a message with // to send it as text)", name)
+		return nil
+	}
+}
+
+// cancelEdit abandons an edit started with /edit and clears the input.
+func (m *model) cancelEdit() {
+	if m.editDepth < 0 {
+		m.notice = "No edit in progress."
+		return
+	}
+	m.dropEdit()
+	m.notice = "Edit cancelled."
+}
+
+// dropEdit abandons an edit in progress, if any, clearing the input and the
+// images carried over from the edited message.
+func (m *model) dropEdit() {
+	if m.editDepth < 0 {
+		return
+	}
+	m.attachments = m.attachments[min(m.editImages, len(m.attachments)):]
+	m.editImages = 0
+	m.editDepth = -1
+	m.textArea.Reset()
+}
+
+// runMultimodelCommand executes a chat command typed into the multimodel
+// input area and records feedback in m.notice.
+func (m *multimodelModel) runMultimodelCommand(input string) tea.Cmd {
+	name := strings.Fields(input)[0]
+
+	switch name {
+	case "/attach":
+		m.notice = queueAttachment(&m.attachments, input)
+	case "/detach":
+		m.attachments = nil
+		m.notice = "Cleared pending attachments."
+	case "/think":
+		think, ok := parseThinkArg(strings.Fields(input)[1:])
+		if !ok {
+			m.notice = "Usage: /think on|off|default"
+			return nil
+		}
+		m.think = think
+		m.notice = thinkLabel(think)
+	case "/schema":
+		schema, notice := parseSchemaArg(input)
+		if notice == "" {
+			m.sessionSchema = schema
+			notice = "Session schema cleared."
+			if schema != nil {
+				notice = "Session schema set for all columns."
+			}
+		}
+		m.notice = notice
+	case "/diff":
+		m.notice = m.setDiff(strings.Fields(input)[1:])
+	case "/judge":
+		if m.judging || m.isLoading {
+			m.notice = "Wait for the current round to finish."
+			return nil
+		}
+		m.showScoreboard = false
+		return m.startJudging()
+	case "/scoreboard":
+		m.showScoreboard = !m.showScoreboard
+	case "/clear":
+		m.notice = m.clearColumns(strings.Fields(input)[1:])
+	case "/sync":
+		m.notice = m.syncHistories(strings.Fields(input)[1:])
+	case "/shared":
+		m.sharedHistory = !m.sharedHistory
+		m.notice = "Each column now sees only its own history."
+		if m.sharedHistory {
+			m.notice = "Every column now sees the prompts and all columns' answers."
+		}
+	case "/mute", "/solo", "/unmute":
+		m.notice = m.setMuted(name, strings.Fields(input)[1:])
+	case "/rank":
+		ranks, notice := m.parseRankArg(strings.Fields(input)[1:])
+		if notice == "" {
+			notice = m.castVote(ranks)
+		}
+		m.notice = notice
+	default:
+		m.notice = fmt.Sprintf("Unknown command: %s (start a message with // to send it as text)", name)
+	}
+	return nil
+}
+
+// setDiff handles the arguments of a "/diff <a> <b> [lines]" or "/diff off"
+// command and returns the notice to show.
+func (m *multimodelModel) setDiff(args []string) string {
+	const usage = "Usage: /diff <column> <column> [lines] or /diff off"
+	if len(args) == 1 && args[0] == "off" {
+		m.diff = nil
+		return ""
+	}
+	if len(args) < 2 || len(args) > 3 || (len(args) == 3 && args[2] != "lines" && args[2] != "words") {
+		return usage
+	}
+
+	var cols [2]int
+	for k, arg := range args[:2] {
+		n, err := strconv.Atoi(arg)
+		i, ok := m.columnAt(n)
+		if err != nil || !ok {
+			return fmt.Sprintf("No column %s. %s", arg, usage)
+		}
+		cols[k] = i
+	}
+	if cols[0] == cols[1] {
+		return "Pick two different columns to compare."
+	}
+
+	m.diff = &diffState{a: cols[0], b: cols[1], lines: len(args) == 3 && args[2] == "lines"}
+	m.refreshComparisons()
+	return ""
+}
+
+// parseThinkArg parses the argument of a /think command. It returns nil for
+// "default", meaning the setting is left to the model.
+func parseThinkArg(args []string) (*bool, bool) {
+	if len(args) != 1 {
+		return nil, false
+	}
+	switch args[0] {
+	case "on":
+		on := true
+		return &on, true
+	case "off":
+		off := false
+		return &off, true
+	case "default":
+		return nil, true
+	}
+	return nil, false
+}
+
+// parseSchemaArg handles the argument of a "/schema <path>|off" command. It
+// returns the loaded schema, or nil for "off", and a non-empty notice on error.
+func parseSchemaArg(input string) (map[string]any, string) {
+	arg := strings.TrimSpace(strings.TrimPrefix(input, "/schema"))
+	switch arg {
+	case "":
+		return nil, "Usage: /schema <path>|off"
+	case "off":
+		return nil, ""
+	}
+	schema, err := loadSchemaFile(arg)
+	if err != nil {
+		return nil, err.Error()
+	}
+	return schema, ""
+}
+
+// queueAttachment loads the file named in an "/attach <path>" command and
+// appends it to pending. It returns a notice describing the outcome.
+func queueAttachment(pending *[]attachment, input string) string {
+	path := strings.TrimSpace(strings.TrimPrefix(input, "/attach"))
+	if path == "" {
+		return "Usage: /attach <path>"
+	}
+	a, err := loadAttachment(path)
+	if err != nil {
+		return err.Error()
+	}
+	*pending = append(*pending, a)
+	return fmt.Sprintf("Attached %s (%d pending).", a.name, len(*pending))
+}
+
+// lastIndexOfRole returns the index of the last message with the given role,
+// or -1 if there is none.
+func lastIndexOfRole(history []chatMessage, role string) int {
+	for i := len(history) - 1; i >= 0; i-- {
+		if history[i].Role == role {
+			return i
+		}
+	}
+	return -1
+}
+
+// userTurnIndex returns the index in history of the n-th user message
+// (1-based). When n is 0, the last user message is returned. It returns -1
+// if no such message exists.
+func userTurnIndex(history []chatMessage, n int) int {
+	if n == 0 {
+		return lastIndexOfRole(history, "user")
+	}
+	count := 0
+	for i, msg := range history {
+		if msg.Role == "user" {
+			count++
+			if count == n {
+				return i
+			}
+		}
+	}
+	return -1
+}
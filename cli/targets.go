@@ -0,0 +1,89 @@
+// cli/targets.go
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parseTargets selects the lanes a message is sent to. A leading "@2" or
+// "@1,3" sends the rest of the message to those columns only, regardless of
+// muting; otherwise every assigned column that is not muted receives it. It
+// returns the targeted lane indices, the message without the prefix, and a
+// notice when nothing can be sent.
+func (m *multimodelModel) parseTargets(input string) (map[int]bool, string, string) {
+	targets := map[int]bool{}
+	if strings.HasPrefix(input, "@") {
+		prefix, text, _ := strings.Cut(input, " ")
+		text = strings.TrimSpace(text)
+		if text == "" {
+			return nil, "", "Usage: @<column>[,<column>...] <message>"
+		}
+		for _, arg := range strings.Split(strings.TrimPrefix(prefix, "@"), ",") {
+			n, err := strconv.Atoi(arg)
+			i, ok := m.columnAt(n)
+			if err != nil || !ok {
+				return nil, "", fmt.Sprintf("No column %s.", arg)
+			}
+			targets[i] = true
+		}
+		return targets, text, ""
+	}
+
+	for _, i := range m.assignedColumns() {
+		if !m.columnResponses[i].muted {
+			targets[i] = true
+		}
+	}
+	if len(targets) == 0 {
+		return nil, "", "All columns are muted. Use /unmute or @N to send to a column."
+	}
+	return targets, input, ""
+}
+
+// roundColumns returns the assigned lanes the latest message was sent to.
+func (m *multimodelModel) roundColumns() []int {
+	var cols []int
+	for _, i := range m.assignedColumns() {
+		if m.targets == nil || m.targets[i] {
+			cols = append(cols, i)
+		}
+	}
+	return cols
+}
+
+// setMuted handles /mute, /solo and /unmute. /mute toggles the listed
+// columns, /solo mutes every column but the listed ones, and /unmute clears
+// the listed columns or, without arguments, all of them.
+func (m *multimodelModel) setMuted(command string, args []string) string {
+	listed := map[int]bool{}
+	for _, arg := range args {
+		n, err := strconv.Atoi(arg)
+		i, ok := m.columnAt(n)
+		if err != nil || !ok {
+			return fmt.Sprintf("No column %s.", arg)
+		}
+		listed[i] = true
+	}
+	if len(listed) == 0 && command != "/unmute" {
+		return fmt.Sprintf("Usage: %s <column> [<column> ...]", command)
+	}
+
+	for _, i := range m.assignedColumns() {
+		col := &m.columnResponses[i]
+		switch command {
+		case "/mute":
+			if listed[i] {
+				col.muted = !col.muted
+			}
+		case "/solo":
+			col.muted = !listed[i]
+		case "/unmute":
+			if len(listed) == 0 || listed[i] {
+				col.muted = false
+			}
+		}
+	}
+	return ""
+}
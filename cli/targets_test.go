@@ -0,0 +1,87 @@
+// cli/targets_test.go
+package cli
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestMultimodelTargetedSend(t *testing.T) {
+	cfg := &Config{Hosts: []Host{
+		{Name: "A", URL: "http://a", Models: []string{"m"}},
+		{Name: "B", URL: "http://b", Models: []string{"m"}},
+		{Name: "C", URL: "http://c", Models: []string{"m"}},
+	}}
+	m := initialMultimodelModel(cfg)
+	for i := range m.assignments {
+		m.assignments[i].selectedModel = "m"
+		m.assignments[i].isAssigned = true
+	}
+	m.state = multimodelViewChat
+	m.width, m.height = 120, 30
+	send := func(text string) {
+		m.textArea.SetValue(text)
+		m.updateChat(tea.KeyMsg{Type: tea.KeyEnter})
+	}
+	users := func() []int {
+		counts := make([]int, len(m.columnResponses))
+		for i, col := range m.columnResponses {
+			for _, msg := range col.chatHistory {
+				if msg.Role == "user" {
+					counts[i]++
+				}
+			}
+		}
+		return counts
+	}
+
+	send("@2 only you")
+	if got := users(); got[0] != 0 || got[1] != 1 || got[2] != 0 {
+		t.Fatalf("expected only column 2 to receive the message, got %v", got)
+	}
+	if m.columnResponses[1].chatHistory[0].Content != "only you" {
+		t.Fatalf("expected the target prefix to be stripped, got %q", m.columnResponses[1].chatHistory[0].Content)
+	}
+	if groups := m.laneGroups(); len(groups) != 1 || groups[0][0] != 1 {
+		t.Fatalf("expected a single lane to be streamed, got %v", groups)
+	}
+	m.isLoading = false
+
+	// Muted columns are skipped unless targeted explicitly.
+	m.notice = m.setMuted("/mute", []string{"3"})
+	send("everyone else")
+	if got := users(); got[0] != 1 || got[1] != 2 || got[2] != 0 {
+		t.Fatalf("expected the muted column to be skipped, got %v", got)
+	}
+	m.isLoading = false
+	send("@1,3 you two")
+	if got := users(); got[0] != 2 || got[1] != 2 || got[2] != 1 {
+		t.Fatalf("expected columns 1 and 3 to receive the message, got %v", got)
+	}
+	if cols := m.roundColumns(); len(cols) != 2 || cols[0] != 0 || cols[1] != 2 {
+		t.Fatalf("expected the round to cover columns 1 and 3, got %v", cols)
+	}
+	m.isLoading = false
+
+	m.setMuted("/solo", []string{"2"})
+	if !m.columnResponses[0].muted || m.columnResponses[1].muted || !m.columnResponses[2].muted {
+		t.Fatalf("expected /solo to mute every other column")
+	}
+	m.setMuted("/mute", []string{"2"})
+	send("nobody")
+	if m.notice == "" || m.isLoading {
+		t.Fatalf("expected a notice when every column is muted")
+	}
+	m.setMuted("/unmute", nil)
+	for i, col := range m.columnResponses {
+		if col.muted {
+			t.Fatalf("expected column %d to be unmuted", i+1)
+		}
+	}
+
+	send("@9 nobody")
+	if m.notice != "No column 9." {
+		t.Fatalf("expected an unknown column to be rejected, got %q", m.notice)
+	}
+}
@@ -0,0 +1,104 @@
+// cli/thinking.go
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	thinkOpenTag  = "<think>"
+	thinkCloseTag = "</think>"
+)
+
+// thinkSplitter separates inline <think>...</think> reasoning from the answer
+// in a streamed response. Tags may be split across chunks, so a trailing
+// partial tag is held back until the next chunk arrives.
+type thinkSplitter struct {
+	inThink bool
+	pending string
+}
+
+// feed consumes the next chunk of streamed content and returns the parts that
+// belong to the answer and to the reasoning.
+func (s *thinkSplitter) feed(chunk string) (answer, thinking string) {
+	var ans, thk strings.Builder
+	buf := s.pending + chunk
+	s.pending = ""
+
+	for buf != "" {
+		tag := thinkOpenTag
+		out := &ans
+		if s.inThink {
+			tag = thinkCloseTag
+			out = &thk
+		}
+
+		if idx := strings.Index(buf, tag); idx >= 0 {
+			out.WriteString(buf[:idx])
+			buf = buf[idx+len(tag):]
+			s.inThink = !s.inThink
+			continue
+		}
+
+		keep := partialTagSuffix(buf, tag)
+		out.WriteString(buf[:len(buf)-keep])
+		s.pending = buf[len(buf)-keep:]
+		break
+	}
+	return ans.String(), thk.String()
+}
+
+// flush returns any held-back text at the end of a stream and resets the
+// splitter for the next response.
+func (s *thinkSplitter) flush() (answer, thinking string) {
+	rest := s.pending
+	inThink := s.inThink
+	*s = thinkSplitter{}
+	if inThink {
+		return "", rest
+	}
+	return rest, ""
+}
+
+// partialTagSuffix returns the length of the longest suffix of s that is a
+// proper prefix of tag.
+func partialTagSuffix(s, tag string) int {
+	for n := len(tag) - 1; n > 0; n-- {
+		if strings.HasSuffix(s, tag[:n]) {
+			return n
+		}
+	}
+	return 0
+}
+
+// renderThinking renders reasoning text as a dimmed section. When collapsed,
+// only a one-line summary is shown.
+func renderThinking(thinking string, expanded bool, width int) string {
+	thinking = strings.TrimSpace(thinking)
+	if thinking == "" {
+		return ""
+	}
+	style := lipgloss.NewStyle().Faint(true).Italic(true)
+	if !expanded {
+		return style.Render(fmt.Sprintf("▸ Thinking (%d chars, ctrl+t to expand)", len(thinking)))
+	}
+	if width > 0 {
+		style = style.Width(width)
+	}
+	return style.Render("▾ Thinking\n" + thinking)
+}
+
+// thinkLabel describes a think setting for the chat header.
+func thinkLabel(think *bool) string {
+	switch {
+	case think == nil:
+		return "Think: default"
+	case *think:
+		return "Think: on"
+	default:
+		return "Think: off"
+	}
+}
@@ -0,0 +1,71 @@
+// cli/thinking_test.go
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestThinkSplitter(t *testing.T) {
+	var s thinkSplitter
+	var answer, thinking strings.Builder
+
+	// Tags split across chunk boundaries must still be recognized.
+	for _, chunk := range []string{"<thi", "nk>step one", " step two</th", "ink>\n\nThe answer", " is 4.<"} {
+		a, th := s.feed(chunk)
+		answer.WriteString(a)
+		thinking.WriteString(th)
+	}
+	a, th := s.flush()
+	answer.WriteString(a)
+	thinking.WriteString(th)
+
+	if got := thinking.String(); got != "step one step two" {
+		t.Errorf("unexpected thinking: %q", got)
+	}
+	if got := answer.String(); got != "\n\nThe answer is 4.<" {
+		t.Errorf("unexpected answer: %q", got)
+	}
+}
+
+func TestThinkingExcludedFromHistoryPayload(t *testing.T) {
+	cfg := &Config{Hosts: []Host{{Name: "HostA", URL: "http://x", Models: []string{"m1"}}}}
+	m := initialModel(cfg)
+	m.state = viewChat
+	m.width, m.height = 100, 30
+
+	m.session.append(chatMessage{Role: "user", Content: "2+2?"})
+	m.chatHistory = m.session.path()
+	m.isLoading = true
+
+	for _, msg := range []tea.Msg{
+		streamThinkingMsg("adding numbers"),
+		streamChunkMsg("<think>more</think>4"),
+		streamEndMsg{meta: LLMResponseMeta{Done: true}},
+	} {
+		m2, _ := m.Update(msg)
+		m = m2.(*model)
+	}
+
+	reply := m.chatHistory[len(m.chatHistory)-1]
+	if reply.Content != "4" || reply.thinking != "adding numbersmore" {
+		t.Fatalf("expected answer and thinking to be separated, got %+v", reply)
+	}
+
+	r := newChatRequest(cfg, cfg.Hosts[0], "m1", m.chatHistory)
+	body := r.payload()
+	if _, ok := body["think"]; ok {
+		t.Error("expected think to be omitted when unset")
+	}
+	for _, msg := range body["messages"].([]chatMessage) {
+		if strings.Contains(msg.Content, "more") {
+			t.Errorf("expected thinking to be excluded from sent history, got %q", msg.Content)
+		}
+	}
+
+	if out := m.View(); !strings.Contains(out, "Thinking (") {
+		t.Errorf("expected collapsed thinking section in view")
+	}
+}
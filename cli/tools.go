@@ -0,0 +1,278 @@
+// cli/tools.go
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// toolCall is a function call requested by the model in a chat response.
+type toolCall struct {
+	Function toolCallFunction `json:"function"`
+}
+
+// toolCallFunction names the tool to run and carries its arguments.
+type toolCallFunction struct {
+	Name      string         `json:"name"`
+	Arguments map[string]any `json:"arguments"`
+}
+
+// String formats the call for display, e.g. read_file({"path":"go.mod"}).
+func (c toolCall) String() string {
+	args, _ := json.Marshal(c.Function.Arguments)
+	return fmt.Sprintf("%s(%s)", c.Function.Name, args)
+}
+
+// localTool is a tool the model may call. Its parameters are described with
+// a JSON Schema object and run executes it on the local machine.
+type localTool struct {
+	name        string
+	description string
+	parameters  map[string]any
+	run         func(args map[string]any) (string, error)
+}
+
+// builtinTools is the registry of tools offered to models when tools are enabled.
+var builtinTools = []localTool{
+	{
+		name:        "read_file",
+		description: "Read a UTF-8 text file from the local filesystem and return its contents.",
+		parameters:  toolParameters(map[string]string{"path": "Path of the file to read."}, "path"),
+		run:         runReadFile,
+	},
+	{
+		name:        "list_directory",
+		description: "List the entries of a local directory. Directories are marked with a trailing slash.",
+		parameters:  toolParameters(map[string]string{"path": "Directory to list. Defaults to the current directory."}),
+		run:         runListDirectory,
+	},
+	{
+		name:        "current_time",
+		description: "Return the current local date and time, optionally in an IANA time zone such as Europe/Berlin.",
+		parameters:  toolParameters(map[string]string{"timezone": "Optional IANA time zone name."}),
+		run:         runCurrentTime,
+	},
+	{
+		name:        "calculator",
+		description: "Evaluate an arithmetic expression using + - * / %, parentheses and the functions sqrt, pow, abs, floor, ceil.",
+		parameters:  toolParameters(map[string]string{"expression": "Expression to evaluate, e.g. (2 + 3) * pow(2, 8)."}, "expression"),
+		run:         runCalculator,
+	},
+}
+
+// toolParameters builds a JSON Schema object with string properties.
+func toolParameters(props map[string]string, required ...string) map[string]any {
+	properties := map[string]any{}
+	for name, desc := range props {
+		properties[name] = map[string]any{"type": "string", "description": desc}
+	}
+	return map[string]any{
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	}
+}
+
+// toolSchemas returns the tool definitions sent in the tools field of /api/chat.
+func toolSchemas(tools []localTool) []map[string]any {
+	schemas := make([]map[string]any, len(tools))
+	for i, t := range tools {
+		schemas[i] = map[string]any{
+			"type": "function",
+			"function": map[string]any{
+				"name":        t.name,
+				"description": t.description,
+				"parameters":  t.parameters,
+			},
+		}
+	}
+	return schemas
+}
+
+// runToolCall executes call with the matching built-in tool and returns the
+// message that reports its result back to the model. Failures are reported
+// in the message content so the model can react to them.
+func runToolCall(call toolCall) chatMessage {
+	result := chatMessage{Role: "tool", ToolName: call.Function.Name}
+	for _, t := range builtinTools {
+		if t.name != call.Function.Name {
+			continue
+		}
+		out, err := t.run(call.Function.Arguments)
+		if err != nil {
+			result.Content = "error: " + err.Error()
+		} else {
+			result.Content = out
+		}
+		return result
+	}
+	result.Content = fmt.Sprintf("error: unknown tool %q", call.Function.Name)
+	return result
+}
+
+// stringArg returns the string argument name from args, or def if absent.
+func stringArg(args map[string]any, name, def string) string {
+	if v, ok := args[name].(string); ok && v != "" {
+		return v
+	}
+	return def
+}
+
+func runReadFile(args map[string]any) (string, error) {
+	path := stringArg(args, "path", "")
+	if path == "" {
+		return "", fmt.Errorf("path is required")
+	}
+	a, err := loadAttachment(path)
+	if err != nil {
+		return "", err
+	}
+	if a.text == "" && a.image != "" {
+		return "", fmt.Errorf("%s is an image, not a text file", a.name)
+	}
+	return a.text, nil
+}
+
+func runListDirectory(args map[string]any) (string, error) {
+	entries, err := os.ReadDir(stringArg(args, "path", "."))
+	if err != nil {
+		return "", err
+	}
+	names := make([]string, len(entries))
+	for i, e := range entries {
+		names[i] = e.Name()
+		if e.IsDir() {
+			names[i] += "/"
+		}
+	}
+	sort.Strings(names)
+	return strings.Join(names, "\n"), nil
+}
+
+func runCurrentTime(args map[string]any) (string, error) {
+	now := time.Now()
+	if tz := stringArg(args, "timezone", ""); tz != "" {
+		loc, err := time.LoadLocation(tz)
+		if err != nil {
+			return "", err
+		}
+		now = now.In(loc)
+	}
+	return now.Format("Monday, 2006-01-02 15:04:05 MST"), nil
+}
+
+func runCalculator(args map[string]any) (string, error) {
+	expr, err := parser.ParseExpr(stringArg(args, "expression", ""))
+	if err != nil {
+		return "", fmt.Errorf("invalid expression: %w", err)
+	}
+	v, err := evalExpr(expr)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64), nil
+}
+
+// evalExpr evaluates an arithmetic expression parsed with go/parser.
+func evalExpr(e ast.Expr) (float64, error) {
+	switch e := e.(type) {
+	case *ast.BasicLit:
+		if e.Kind != token.INT && e.Kind != token.FLOAT {
+			return 0, fmt.Errorf("unsupported literal %s", e.Value)
+		}
+		return strconv.ParseFloat(e.Value, 64)
+
+	case *ast.ParenExpr:
+		return evalExpr(e.X)
+
+	case *ast.UnaryExpr:
+		x, err := evalExpr(e.X)
+		if err != nil {
+			return 0, err
+		}
+		switch e.Op {
+		case token.SUB:
+			return -x, nil
+		case token.ADD:
+			return x, nil
+		}
+		return 0, fmt.Errorf("unsupported operator %s", e.Op)
+
+	case *ast.BinaryExpr:
+		x, err := evalExpr(e.X)
+		if err != nil {
+			return 0, err
+		}
+		y, err := evalExpr(e.Y)
+		if err != nil {
+			return 0, err
+		}
+		switch e.Op {
+		case token.ADD:
+			return x + y, nil
+		case token.SUB:
+			return x - y, nil
+		case token.MUL:
+			return x * y, nil
+		case token.QUO:
+			if y == 0 {
+				return 0, fmt.Errorf("division by zero")
+			}
+			return x / y, nil
+		case token.REM:
+			if y == 0 {
+				return 0, fmt.Errorf("division by zero")
+			}
+			return math.Mod(x, y), nil
+		}
+		return 0, fmt.Errorf("unsupported operator %s", e.Op)
+
+	case *ast.CallExpr:
+		fn, ok := e.Fun.(*ast.Ident)
+		if !ok {
+			return 0, fmt.Errorf("unsupported function call")
+		}
+		vals := make([]float64, len(e.Args))
+		for i, arg := range e.Args {
+			v, err := evalExpr(arg)
+			if err != nil {
+				return 0, err
+			}
+			vals[i] = v
+		}
+		return callMathFunc(fn.Name, vals)
+	}
+	return 0, fmt.Errorf("unsupported expression")
+}
+
+// callMathFunc applies one of the calculator's named functions.
+func callMathFunc(name string, args []float64) (float64, error) {
+	unary := map[string]func(float64) float64{
+		"sqrt":  math.Sqrt,
+		"abs":   math.Abs,
+		"floor": math.Floor,
+		"ceil":  math.Ceil,
+	}
+	if f, ok := unary[name]; ok {
+		if len(args) != 1 {
+			return 0, fmt.Errorf("%s takes one argument", name)
+		}
+		return f(args[0]), nil
+	}
+	if name == "pow" {
+		if len(args) != 2 {
+			return 0, fmt.Errorf("pow takes two arguments")
+		}
+		return math.Pow(args[0], args[1]), nil
+	}
+	return 0, fmt.Errorf("unknown function %s", name)
+}
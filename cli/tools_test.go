@@ -0,0 +1,119 @@
+// cli/tools_test.go
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestCalculatorTool(t *testing.T) {
+	cases := map[string]string{
+		"1 + 2 * 3":          "7",
+		"(1 + 2) * 3":        "9",
+		"-4 / 2":             "-2",
+		"pow(2, 10) % 1000":  "24",
+		"sqrt(16) + abs(-1)": "5",
+	}
+	for expr, want := range cases {
+		got, err := runCalculator(map[string]any{"expression": expr})
+		if err != nil || got != want {
+			t.Errorf("calculator(%q) = %q, %v; want %q", expr, got, err, want)
+		}
+	}
+	for _, expr := range []string{"1 / 0", "x + 1", "\"a\"", "os.Exit(1)"} {
+		if _, err := runCalculator(map[string]any{"expression": expr}); err == nil {
+			t.Errorf("calculator(%q) should have failed", expr)
+		}
+	}
+}
+
+func TestRunToolCall(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("alpha"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(dir, "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	read := runToolCall(toolCall{Function: toolCallFunction{Name: "read_file", Arguments: map[string]any{"path": filepath.Join(dir, "a.txt")}}})
+	if read.Role != "tool" || read.ToolName != "read_file" || read.Content != "alpha" {
+		t.Errorf("unexpected read_file result: %+v", read)
+	}
+	list := runToolCall(toolCall{Function: toolCallFunction{Name: "list_directory", Arguments: map[string]any{"path": dir}}})
+	if list.Content != "a.txt\nsub/" {
+		t.Errorf("unexpected list_directory result: %q", list.Content)
+	}
+	unknown := runToolCall(toolCall{Function: toolCallFunction{Name: "rm_rf"}})
+	if !strings.HasPrefix(unknown.Content, "error:") {
+		t.Errorf("expected error for unknown tool, got %q", unknown.Content)
+	}
+}
+
+func TestToolCallConfirmationFlow(t *testing.T) {
+	cfg := &Config{Tools: true, Hosts: []Host{{Name: "HostA", URL: "http://x", Models: []string{"m1"}}}}
+	m := initialModel(cfg)
+	m.state = viewChat
+	m.program = &tea.Program{}
+	m.session.append(chatMessage{Role: "user", Content: "what is 6*7?"})
+	m.chatHistory = m.session.path()
+
+	call := toolCall{Function: toolCallFunction{Name: "calculator", Arguments: map[string]any{"expression": "6*7"}}}
+	for _, msg := range []tea.Msg{streamToolCallsMsg{call}, streamEndMsg{meta: LLMResponseMeta{Done: true}}} {
+		m2, _ := m.Update(msg)
+		m = m2.(*model)
+	}
+	if len(m.pendingToolCalls) != 1 {
+		t.Fatalf("expected a pending tool call, got %d", len(m.pendingToolCalls))
+	}
+	if last := m.chatHistory[len(m.chatHistory)-1]; last.Role != "assistant" || len(last.ToolCalls) != 1 {
+		t.Fatalf("expected assistant message carrying the tool call, got %+v", last)
+	}
+
+	m2, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	m = m2.(*model)
+	last := m.chatHistory[len(m.chatHistory)-1]
+	if last.Role != "tool" || last.Content != "42" {
+		t.Fatalf("expected tool result in history, got %+v", last)
+	}
+	if !m.isLoading || len(m.pendingToolCalls) != 0 {
+		t.Fatalf("expected results to be sent back to the model")
+	}
+
+	r := newChatRequest(cfg, cfg.Hosts[0], "m1", m.chatHistory)
+	r.tools = toolSchemas(builtinTools)
+	if tools, ok := r.payload()["tools"].([]map[string]any); !ok || len(tools) != len(builtinTools) {
+		t.Fatalf("expected tool schemas in payload")
+	}
+}
+
+func TestToolCallConfirmationWaitsForStreamEnd(t *testing.T) {
+	cfg := &Config{Tools: true, Hosts: []Host{{Name: "HostA", URL: "http://x", Models: []string{"m1"}}}}
+	m := initialModel(cfg)
+	m.state = viewChat
+	m.program = &tea.Program{}
+	m.session.append(chatMessage{Role: "user", Content: "what is 6*7?"})
+	m.chatHistory = m.session.path()
+	m.isLoading = true
+
+	call := toolCall{Function: toolCallFunction{Name: "calculator", Arguments: map[string]any{"expression": "6*7"}}}
+	m2, _ := m.Update(streamToolCallsMsg{call})
+	m = m2.(*model)
+	m2, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	m = m2.(*model)
+	if cmd != nil || len(m.pendingToolCalls) != 1 || len(m.chatHistory) != 1 {
+		t.Fatalf("expected y to be ignored while the reply streams; pending=%d history=%+v", len(m.pendingToolCalls), m.chatHistory)
+	}
+
+	m2, _ = m.Update(streamEndMsg{meta: LLMResponseMeta{Done: true}})
+	m = m2.(*model)
+	m2, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	m = m2.(*model)
+	if len(m.chatHistory) != 3 || m.chatHistory[1].Role != "assistant" || m.chatHistory[2].Role != "tool" {
+		t.Fatalf("expected the tool result after the assistant message, got %+v", m.chatHistory)
+	}
+}
@@ -0,0 +1,341 @@
+// cli/votes.go
+package cli
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+const (
+	// defaultVotesFile is where votes are stored when votes_file is not set.
+	defaultVotesFile = "votes.jsonl"
+	// eloStart is the rating every model or persona starts from.
+	eloStart = 1000.0
+	// eloK is the Elo update factor per pairwise comparison.
+	eloK = 32.0
+)
+
+// VoteRecord is one line of the votes file: the user's ranking of the
+// columns for one turn of a multimodel session.
+type VoteRecord struct {
+	// Time is when the vote was cast.
+	Time time.Time `json:"time"`
+	// Session identifies the multimodel session the vote was cast in.
+	Session string `json:"session"`
+	// Turn is the number of the prompt within the session, from 1.
+	Turn int `json:"turn"`
+	// Prompt is the user prompt the columns answered.
+	Prompt string `json:"prompt"`
+	// Ranking lists every answered column with its rank.
+	Ranking []VoteEntry `json:"ranking"`
+}
+
+// VoteEntry is a ranked column in a VoteRecord.
+type VoteEntry struct {
+	// Rank is 1 for the best column. Columns with equal rank are tied.
+	Rank int `json:"rank"`
+	// Host is the name of the host that answered.
+	Host string `json:"host"`
+	// Model is the model that answered.
+	Model string `json:"model"`
+	// SystemPrompt is the system prompt the lane used.
+	SystemPrompt string `json:"systemprompt,omitempty"`
+	// Parameters are the generation parameters the lane used.
+	Parameters Parameters `json:"parameters"`
+	// Response is the answer that was ranked.
+	Response string `json:"response"`
+}
+
+// persona identifies the entry's model together with its system prompt and
+// parameters, so differently prompted lanes of one model are rated apart.
+func (e VoteEntry) persona() string {
+	key := e.Model
+	if e.SystemPrompt != "" {
+		key += " | " + truncate(e.SystemPrompt, 40)
+	}
+	if params := formatParams(e.Parameters); params != "" {
+		key += " | " + params
+	}
+	return key
+}
+
+// appendVote writes rec as a new line of the votes file at path.
+func appendVote(path string, rec VoteRecord) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("could not open votes file: %w", err)
+	}
+	defer f.Close()
+	b, _ := json.Marshal(rec)
+	if _, err := f.Write(append(b, '\n')); err != nil {
+		return fmt.Errorf("could not write votes file: %w", err)
+	}
+	return nil
+}
+
+// readVotes reads the votes file at path. When a turn was voted on more than
+// once, only the last vote counts. Records are returned in the order of
+// their final vote.
+func readVotes(path string) ([]VoteRecord, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not open votes file: %w", err)
+	}
+	defer f.Close()
+
+	var records []VoteRecord
+	latest := map[string]int{}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var rec VoteRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		key := rec.Session + "\x00" + strconv.Itoa(rec.Turn)
+		if i, ok := latest[key]; ok {
+			records[i].Ranking = nil // superseded by this vote
+		}
+		latest[key] = len(records)
+		records = append(records, rec)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("could not read votes file: %w", err)
+	}
+
+	kept := records[:0]
+	for _, rec := range records {
+		if rec.Ranking != nil {
+			kept = append(kept, rec)
+		}
+	}
+	return kept, nil
+}
+
+// voteStanding is a model's or persona's record across all votes.
+type voteStanding struct {
+	name   string
+	votes  int
+	firsts int
+	wins   int
+	losses int
+	ties   int
+	elo    float64
+}
+
+// winRate is the share of votes in which the entry was ranked first.
+func (s voteStanding) winRate() float64 {
+	if s.votes == 0 {
+		return 0
+	}
+	return float64(s.firsts) / float64(s.votes)
+}
+
+// tallyVotes computes standings from records. Every pair of columns in a
+// vote counts as one game for the Elo rating, with equal ranks as a draw.
+// by selects the grouping: "model" or "persona".
+func tallyVotes(records []VoteRecord, by string) []voteStanding {
+	standings := map[string]*voteStanding{}
+	get := func(e VoteEntry) *voteStanding {
+		name := e.Model
+		if by == "persona" {
+			name = e.persona()
+		}
+		s, ok := standings[name]
+		if !ok {
+			s = &voteStanding{name: name, elo: eloStart}
+			standings[name] = s
+		}
+		return s
+	}
+
+	for _, rec := range records {
+		for _, e := range rec.Ranking {
+			s := get(e)
+			s.votes++
+			if e.Rank == 1 {
+				s.firsts++
+			}
+		}
+		for i := 0; i < len(rec.Ranking); i++ {
+			for j := i + 1; j < len(rec.Ranking); j++ {
+				a, b := get(rec.Ranking[i]), get(rec.Ranking[j])
+				if a == b {
+					continue
+				}
+				score := 0.5
+				switch {
+				case rec.Ranking[i].Rank < rec.Ranking[j].Rank:
+					score = 1
+					a.wins++
+					b.losses++
+				case rec.Ranking[i].Rank > rec.Ranking[j].Rank:
+					score = 0
+					a.losses++
+					b.wins++
+				default:
+					a.ties++
+					b.ties++
+				}
+				expected := 1 / (1 + math.Pow(10, (b.elo-a.elo)/400))
+				a.elo += eloK * (score - expected)
+				b.elo -= eloK * (score - expected)
+			}
+		}
+	}
+
+	list := make([]voteStanding, 0, len(standings))
+	for _, s := range standings {
+		list = append(list, *s)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].elo != list[j].elo {
+			return list[i].elo > list[j].elo
+		}
+		return list[i].name < list[j].name
+	})
+	return list
+}
+
+// VotesReport reads the votes file at path and writes win rates and Elo
+// ratings to out, grouped by "model" or by "persona" (model, system prompt
+// and parameters).
+func VotesReport(path, by string, out io.Writer) error {
+	if by != "model" && by != "persona" {
+		return fmt.Errorf("invalid grouping %q: use model or persona", by)
+	}
+	records, err := readVotes(path)
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		fmt.Fprintln(out, "No votes recorded yet.")
+		return nil
+	}
+
+	sessions := map[string]bool{}
+	for _, rec := range records {
+		sessions[rec.Session] = true
+	}
+	fmt.Fprintf(out, "%d votes across %d sessions\n\n", len(records), len(sessions))
+
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "RANK\tELO\tWIN RATE\tVOTES\tW-L-T\t"+strings.ToUpper(by))
+	for i, s := range tallyVotes(records, by) {
+		fmt.Fprintf(tw, "%d\t%.0f\t%.0f%%\t%d\t%d-%d-%d\t%s\n", i+1, s.elo, s.winRate()*100, s.votes, s.wins, s.losses, s.ties, s.name)
+	}
+	return tw.Flush()
+}
+
+// newSessionID identifies a multimodel session in the votes file: the start
+// time plus a random suffix, so sessions started in the same second differ.
+func newSessionID() string {
+	b := make([]byte, 3)
+	rand.Read(b)
+	return time.Now().Format("20060102-150405") + "-" + hex.EncodeToString(b)
+}
+
+// ConfiguredVotesFile returns the votes file set by the config at
+// configPath, or the default when the config sets none or cannot be read.
+func ConfiguredVotesFile(configPath string) string {
+	cfg, err := loadConfig(configPath)
+	if err != nil {
+		return defaultVotesFile
+	}
+	return cfg.votesFile()
+}
+
+// votesFile returns the configured votes file path.
+func (cfg *Config) votesFile() string {
+	if cfg.VotesFile != "" {
+		return cfg.VotesFile
+	}
+	return defaultVotesFile
+}
+
+// castVote records a ranking of the latest answers. ranks maps lane indices
+// to ranks; answered columns missing from it are tied after the ranked ones.
+func (m *multimodelModel) castVote(ranks map[int]int) string {
+	if m.isLoading {
+		return "Wait for the current round to finish."
+	}
+	worst := 0
+	for i, r := range ranks {
+		if m.latestResponse(i) == "" || !slices.Contains(m.roundColumns(), i) {
+			return fmt.Sprintf("%s has no answer to vote for.", m.laneName(i))
+		}
+		worst = max(worst, r)
+	}
+
+	rec := VoteRecord{Time: time.Now(), Session: m.sessionID, Turn: m.turn}
+	for _, i := range m.roundColumns() {
+		answer := m.latestResponse(i)
+		if answer == "" {
+			continue
+		}
+		if rec.Prompt == "" {
+			if u := lastIndexOfRole(m.columnResponses[i].chatHistory, "user"); u >= 0 {
+				rec.Prompt = m.columnResponses[i].chatHistory[u].Content
+			}
+		}
+		rank, ok := ranks[i]
+		if !ok {
+			rank = worst + 1
+		}
+		lane := m.assignments[i]
+		rec.Ranking = append(rec.Ranking, VoteEntry{
+			Rank:         rank,
+			Host:         lane.host.Name,
+			Model:        lane.selectedModel,
+			SystemPrompt: lane.host.SystemPrompt,
+			Parameters:   lane.host.Parameters,
+			Response:     answer,
+		})
+	}
+	if len(rec.Ranking) < 2 {
+		return "Voting needs at least two answered columns."
+	}
+	sort.SliceStable(rec.Ranking, func(a, b int) bool { return rec.Ranking[a].Rank < rec.Ranking[b].Rank })
+
+	if err := appendVote(m.config.votesFile(), rec); err != nil {
+		return err.Error()
+	}
+	m.votes = ranks
+	return fmt.Sprintf("Vote saved to %s.", m.config.votesFile())
+}
+
+// parseRankArg parses the arguments of "/rank 2 1 3": column numbers from
+// best to worst. It returns lane indices mapped to ranks.
+func (m *multimodelModel) parseRankArg(args []string) (map[int]int, string) {
+	if len(args) == 0 {
+		return nil, "Usage: /rank <best column> <next column> ..."
+	}
+	ranks := map[int]int{}
+	for r, arg := range args {
+		n, err := strconv.Atoi(arg)
+		i, ok := m.columnAt(n)
+		if err != nil || !ok {
+			return nil, fmt.Sprintf("No column %s.", arg)
+		}
+		if _, dup := ranks[i]; dup {
+			return nil, fmt.Sprintf("Column %s is listed twice.", arg)
+		}
+		ranks[i] = r + 1
+	}
+	return ranks, ""
+}
@@ -0,0 +1,153 @@
+// cli/votes_test.go
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestMultimodelVoting(t *testing.T) {
+	temp := 0.3
+	votesPath := filepath.Join(t.TempDir(), "votes.jsonl")
+	cfg := &Config{VotesFile: votesPath, Hosts: []Host{
+		{Name: "A", URL: "http://a", Models: []string{"m1"}},
+		{Name: "B", URL: "http://b", Models: []string{"m2"}, SystemPrompt: "be brief", Parameters: Parameters{Temperature: &temp}},
+		{Name: "C", URL: "http://c", Models: []string{"m3"}},
+	}}
+	m := initialMultimodelModel(cfg)
+	m.state = multimodelViewChat
+	m.width, m.height = 120, 40
+	m.turn = 1
+	for i, answer := range []string{"one", "two", "three"} {
+		m.assignments[i].selectedModel = cfg.Hosts[i].Models[0]
+		m.assignments[i].isAssigned = true
+		m.columnResponses[i].chatHistory = []chatMessage{{Role: "user", Content: "count"}, {Role: "assistant", Content: answer}}
+	}
+
+	// ctrl+b votes for the focused column.
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlRight})
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlB})
+	if !strings.Contains(m.notice, "Vote saved") || !strings.Contains(m.View(), "★ best") {
+		t.Fatalf("expected vote to be saved and shown, notice %q", m.notice)
+	}
+
+	// A later ranking of the same turn replaces the first vote.
+	m.runMultimodelCommand("/rank 3 1")
+	records, err := readVotes(votesPath)
+	if err != nil {
+		t.Fatalf("readVotes returned error: %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("expected one vote per turn, got %d", len(records))
+	}
+	rec := records[0]
+	if rec.Prompt != "count" || rec.Turn != 1 || rec.Session != m.sessionID {
+		t.Fatalf("unexpected vote record: %+v", rec)
+	}
+	got := []string{}
+	for _, e := range rec.Ranking {
+		got = append(got, e.Model+":"+string(rune('0'+e.Rank)))
+	}
+	if strings.Join(got, ",") != "m3:1,m1:2,m2:3" {
+		t.Fatalf("unexpected ranking %v", got)
+	}
+	if rec.Ranking[2].SystemPrompt != "be brief" || *rec.Ranking[2].Parameters.Temperature != 0.3 {
+		t.Fatalf("expected lane settings in the vote, got %+v", rec.Ranking[2])
+	}
+
+	m.runMultimodelCommand("/rank 1 1")
+	if !strings.Contains(m.notice, "twice") {
+		t.Fatalf("expected duplicate columns to be rejected, got %q", m.notice)
+	}
+}
+
+func TestVotesReport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "votes.jsonl")
+	vote := func(session string, turn int, entries ...VoteEntry) {
+		if err := appendVote(path, VoteRecord{Session: session, Turn: turn, Ranking: entries}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	vote("s1", 1, VoteEntry{Rank: 1, Model: "big"}, VoteEntry{Rank: 2, Model: "small"})
+	vote("s1", 2, VoteEntry{Rank: 1, Model: "big"}, VoteEntry{Rank: 2, Model: "small", SystemPrompt: "pirate"})
+	vote("s2", 1, VoteEntry{Rank: 1, Model: "small"}, VoteEntry{Rank: 1, Model: "big"})
+
+	standings := tallyVotes(mustReadVotes(t, path), "model")
+	if standings[0].name != "big" || standings[0].elo <= eloStart || standings[1].elo >= eloStart {
+		t.Fatalf("expected big to be rated above small, got %+v", standings)
+	}
+	if standings[0].firsts != 3 || standings[0].wins != 2 || standings[0].ties != 1 {
+		t.Fatalf("unexpected record for big: %+v", standings[0])
+	}
+
+	var out bytes.Buffer
+	if err := VotesReport(path, "persona", &out); err != nil {
+		t.Fatalf("VotesReport returned error: %v", err)
+	}
+	report := out.String()
+	if !strings.Contains(report, "3 votes across 2 sessions") || !strings.Contains(report, "small | pirate") {
+		t.Fatalf("unexpected report:\n%s", report)
+	}
+	if err := VotesReport(path, "host", &out); err == nil {
+		t.Fatal("expected an error for an unknown grouping")
+	}
+}
+
+func mustReadVotes(t *testing.T, path string) []VoteRecord {
+	t.Helper()
+	records, err := readVotes(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return records
+}
+
+func TestVoteNeedsAnAnswer(t *testing.T) {
+	votesPath := filepath.Join(t.TempDir(), "votes.jsonl")
+	cfg := &Config{VotesFile: votesPath, Hosts: []Host{
+		{Name: "A", URL: "http://a", Models: []string{"m1"}},
+		{Name: "B", URL: "http://b", Models: []string{"m2"}},
+		{Name: "C", URL: "http://c", Models: []string{"m3"}},
+	}}
+	m := initialMultimodelModel(cfg)
+	m.state = multimodelViewChat
+	m.width, m.height = 120, 40
+	for i := range m.assignments {
+		m.assignments[i].selectedModel = cfg.Hosts[i].Models[0]
+		m.assignments[i].isAssigned = true
+	}
+	for i, answer := range []string{"one", "two"} {
+		m.columnResponses[i].chatHistory = []chatMessage{{Role: "user", Content: "count"}, {Role: "assistant", Content: answer}}
+	}
+
+	// The third column has no answer, so it cannot be voted best.
+	m.focusedColumn = 2
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlB})
+	if !strings.Contains(m.notice, "no answer to vote for") || strings.Contains(m.View(), "★ best") {
+		t.Fatalf("expected the vote to be rejected, notice %q", m.notice)
+	}
+	if _, err := os.Stat(votesPath); !os.IsNotExist(err) {
+		t.Fatalf("expected no vote to be written, got %v", err)
+	}
+}
+
+func TestConfiguredVotesFileAndSessionIDs(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	os.WriteFile(path, []byte(`{"votes_file": "team-votes.jsonl", "hosts": [{"name": "A", "url": "http://a", "models": ["m"]}]}`), 0o644)
+	if got := ConfiguredVotesFile(path); got != "team-votes.jsonl" {
+		t.Errorf("expected the config's votes file, got %q", got)
+	}
+	if got := ConfiguredVotesFile(filepath.Join(dir, "missing.json")); got != defaultVotesFile {
+		t.Errorf("expected the default votes file without a config, got %q", got)
+	}
+
+	if a, b := newSessionID(), newSessionID(); a == b {
+		t.Errorf("expected sessions started together to get different ids, got %q twice", a)
+	}
+}
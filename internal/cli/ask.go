@@ -0,0 +1,80 @@
+// cmd/gollamacli/ask.go
+package gollamacli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/mwiater/gollamacli/cli"
+	"github.com/spf13/cobra"
+)
+
+var runAsk = cli.Ask
+
+// askOpts holds the flag values for the 'ask' command.
+var askOpts cli.AskOptions
+
+// askStdin is the source of piped prompts; tests replace it.
+var askStdin io.Reader = os.Stdin
+
+// askCmd implements 'ask', which sends a single prompt to a model and streams
+// the answer to stdout without starting the TUI.
+var askCmd = &cobra.Command{
+	Use:   "ask [prompt]",
+	Short: "Send a single prompt and print the answer",
+	Long: `The 'ask' command sends one prompt to a model on a configured host and streams the answer to stdout.
+When input is piped, it is read as the prompt; a prompt given as an argument is placed before the piped text.`,
+	Example: `  gollamacli ask --host "Local Ollama" --model llama3.2 "Why is the sky blue?"
+  cat main.go | gollamacli ask --model qwen3 --param temperature=0.2 "Review this code"`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := askOpts
+		opts.Prompt = strings.Join(args, " ")
+
+		piped, err := readPipedInput(askStdin)
+		if err != nil {
+			return err
+		}
+		if piped != "" {
+			if opts.Prompt != "" {
+				opts.Prompt += "\n\n"
+			}
+			opts.Prompt += piped
+		}
+		if strings.TrimSpace(opts.Prompt) == "" {
+			return fmt.Errorf("no prompt given: pass it as an argument or pipe it on stdin")
+		}
+
+		cmd.SilenceUsage = true
+		return runAsk(cmd.Context(), opts, cmd.OutOrStdout(), cmd.ErrOrStderr())
+	},
+}
+
+// readPipedInput returns the contents of r when it is a pipe or file rather
+// than a terminal. It returns an empty string for interactive terminals.
+func readPipedInput(r io.Reader) (string, error) {
+	if f, ok := r.(*os.File); ok {
+		stat, err := f.Stat()
+		if err != nil || stat.Mode()&os.ModeCharDevice != 0 {
+			return "", nil
+		}
+	}
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return "", fmt.Errorf("could not read stdin: %w", err)
+	}
+	return strings.TrimSpace(string(b)), nil
+}
+
+func init() {
+	rootCmd.AddCommand(askCmd)
+
+	askCmd.Flags().StringVarP(&askOpts.ConfigPath, "config", "c", "config.json", "config file (e.g., config.Authors.json)")
+	askCmd.Flags().StringVar(&askOpts.Host, "host", "", "name or URL of the configured host (default: first host)")
+	askCmd.Flags().StringVar(&askOpts.Model, "model", "", "model to query (default: the host's first model)")
+	askCmd.Flags().StringVar(&askOpts.System, "system", "", "system prompt, overriding the host's")
+	askCmd.Flags().BoolVar(&askOpts.JSON, "json", false, "request JSON output")
+	askCmd.Flags().StringArrayVar(&askOpts.Params, "param", nil, "generation parameter as key=value, e.g. temperature=0.2 (repeatable)")
+	askCmd.Flags().BoolVar(&askOpts.Stats, "stats", false, "print response metadata to stderr")
+}
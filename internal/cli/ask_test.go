@@ -0,0 +1,35 @@
+// cmd/gollamacli/ask_test.go
+package gollamacli
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/mwiater/gollamacli/cli"
+)
+
+func TestAskCmdCombinesArgsAndStdin(t *testing.T) {
+	originalRunAsk, originalStdin := runAsk, askStdin
+	defer func() { runAsk, askStdin = originalRunAsk, originalStdin }()
+
+	var received cli.AskOptions
+	runAsk = func(ctx context.Context, opts cli.AskOptions, out, errOut io.Writer) error {
+		received = opts
+		return nil
+	}
+	askStdin = strings.NewReader("package main\n")
+
+	if err := askCmd.RunE(askCmd, []string{"review", "this"}); err != nil {
+		t.Fatalf("ask returned error: %v", err)
+	}
+	if received.Prompt != "review this\n\npackage main" {
+		t.Fatalf("unexpected prompt: %q", received.Prompt)
+	}
+
+	askStdin = strings.NewReader("")
+	if err := askCmd.RunE(askCmd, nil); err == nil {
+		t.Fatal("expected an error without a prompt")
+	}
+}
@@ -0,0 +1,18 @@
+// cmd/gollamacli/batch.go
+package gollamacli
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// batchCmd groups the subcommands that run prompt files through models
+// without the TUI.
+var batchCmd = &cobra.Command{
+	Use:   "batch",
+	Short: "Run prompt files through models",
+	Long:  `The 'batch' command groups subcommands that send prompts from a file to models on the configured hosts.`,
+}
+
+func init() {
+	rootCmd.AddCommand(batchCmd)
+}
@@ -0,0 +1,40 @@
+// cmd/gollamacli/batch_run.go
+package gollamacli
+
+import (
+	"github.com/mwiater/gollamacli/cli"
+	"github.com/spf13/cobra"
+)
+
+var runBatch = cli.RunBatch
+
+// batchOpts holds the flag values for the 'batch run' command.
+var batchOpts cli.BatchOptions
+
+// batchRunCmd implements 'batch run', which sends every prompt in a JSONL
+// file to each selected host and model and records the results.
+var batchRunCmd = &cobra.Command{
+	Use:   "run <prompts.jsonl>",
+	Short: "Send each prompt in a JSONL file to each host and model",
+	Long: `The 'run' subcommand reads prompts from a JSONL file, one {"id": ..., "prompt": ..., "system": ...} object per line,
+and sends each one to every selected host and model. Results are appended to a JSONL file with the response text and
+timing metadata. Running the same batch again skips prompts that already completed and retries the ones that failed.`,
+	Example: `  gollamacli batch run prompts.jsonl --hosts "Local Ollama" --models llama3.2,qwen3 --concurrency 2`,
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := batchOpts
+		opts.PromptsPath = args[0]
+		cmd.SilenceUsage = true
+		return runBatch(cmd.Context(), opts, cmd.ErrOrStderr())
+	},
+}
+
+func init() {
+	batchCmd.AddCommand(batchRunCmd)
+
+	batchRunCmd.Flags().StringVarP(&batchOpts.ConfigPath, "config", "c", "config.json", "config file (e.g., config.Authors.json)")
+	batchRunCmd.Flags().StringSliceVar(&batchOpts.Hosts, "hosts", nil, "hosts to use, by name or URL (default: all hosts)")
+	batchRunCmd.Flags().StringSliceVar(&batchOpts.Models, "models", nil, "models to run on every host (default: each host's configured models)")
+	batchRunCmd.Flags().StringVarP(&batchOpts.OutPath, "out", "o", "", "results file (default: <prompts>.results.jsonl)")
+	batchRunCmd.Flags().IntVar(&batchOpts.Concurrency, "concurrency", 1, "maximum requests in flight per host URL")
+}
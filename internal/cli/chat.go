@@ -14,14 +14,18 @@ var startGUI = cli.StartGUI
 // but it's common practice with StringVar.
 var cfgFile string
 
+// chatPreset names the multimodel preset to start with.
+var chatPreset string
+
 // chatCmd represents the 'chat' command.
 var chatCmd = &cobra.Command{
 	Use:   "chat",
 	Short: "Start a chat session",
-	Long:  `The 'chat' command starts an interactive chat session with a large language model.`,
+	Long: `The 'chat' command starts an interactive chat session with a large language model.
+With --preset, it starts a multimodel session with the lanes of the named preset.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		configPath := viper.GetString("config")
-		startGUI(configPath)
+		startGUI(configPath, chatPreset)
 	},
 }
 
@@ -33,6 +37,8 @@ func init() {
 	// StringVarP: Target variable, Flag name, Shorthand (e.g., "c"), Default value, Description
 	chatCmd.Flags().StringVarP(&cfgFile, "config", "c", "config.json", "config file (e.g., config.Authors.json)")
 
+	chatCmd.Flags().StringVar(&chatPreset, "preset", "", "start multimodel chat with the named preset from the config")
+
 	// 3. Bind the Cobra flag to Viper
 	// The key in viper will be "config"
 	viper.BindPFlag("config", chatCmd.Flags().Lookup("config"))
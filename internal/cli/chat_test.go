@@ -17,11 +17,12 @@ func TestChatCmd(t *testing.T) {
 	originalStartGUI := startGUI
 	defer func() { startGUI = originalStartGUI }()
 
-	var receivedPath string
+	var receivedPath, receivedPreset string
 	startCalled := false
-	startGUI = func(path string) {
+	startGUI = func(path, preset string) {
 		startCalled = true
 		receivedPath = path
+		receivedPreset = preset
 	}
 
 	viper.Set("config", "test-config.json")
@@ -37,4 +38,14 @@ func TestChatCmd(t *testing.T) {
 	if receivedPath != "test-config.json" {
 		t.Fatalf("expected config path 'test-config.json', got %q", receivedPath)
 	}
+	if receivedPreset != "" {
+		t.Fatalf("expected no preset, got %q", receivedPreset)
+	}
+
+	chatPreset = "shootout"
+	defer func() { chatPreset = "" }()
+	chatCmd.Run(chatCmd, []string{})
+	if receivedPreset != "shootout" {
+		t.Fatalf("expected preset 'shootout', got %q", receivedPreset)
+	}
 }
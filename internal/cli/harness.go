@@ -5,11 +5,11 @@ import (
 	"github.com/spf13/cobra"
 )
 
-// harnessCmd
+// harnessCmd groups the subcommands that benchmark models on Ollama hosts.
 var harnessCmd = &cobra.Command{
 	Use:   "harness",
-	Short: "",
-	Long:  ``,
+	Short: "Benchmark model speed on Ollama hosts",
+	Long:  `The 'harness' command groups subcommands that measure latency and throughput of models with repeatable benchmark suites.`,
 }
 
 func init() {
@@ -0,0 +1,36 @@
+// cmd/gollamacli/harness_compare.go
+package gollamacli
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/mwiater/gollamacli/internal/harness"
+)
+
+var compareHarness = harness.CompareRuns
+
+// harnessCompareDir is the results directory run ids are looked up in.
+var harnessCompareDir string
+
+// harnessCompareCmd implements 'harness compare', which reports how two saved
+// runs differ.
+var harnessCompareCmd = &cobra.Command{
+	Use:   "compare <runA> <runB>",
+	Short: "Compare two saved harness runs",
+	Long: `The 'compare' subcommand reports, per host, model and scenario, how TTFT p50/p95, total latency p50/p95 and
+generation throughput changed from run A to run B. Each run is given as a run id (or a unique prefix), "latest", or the path
+of a result file. Differences are tested with a bootstrap of the difference in means of the warm trials and flagged as
+better or worse when p < 0.05.`,
+	Example: `  gollamacli harness compare 3f9c2a1 latest`,
+	Args:    cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		return compareHarness(harnessCompareDir, args[0], args[1], cmd.OutOrStdout())
+	},
+}
+
+func init() {
+	harnessCmd.AddCommand(harnessCompareCmd)
+
+	harnessCompareCmd.Flags().StringVar(&harnessCompareDir, "results-dir", "harness-results", "directory saved runs are looked up in")
+}
@@ -0,0 +1,48 @@
+// cmd/gollamacli/harness_load.go
+package gollamacli
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/mwiater/gollamacli/internal/harness"
+)
+
+var runHarnessLoad = harness.RunLoad
+
+// harnessLoadOpts holds the flag values for the 'harness load' command.
+var harnessLoadOpts harness.LoadOptions
+
+// harnessLoadCmd implements 'harness load', which load tests the models of a
+// suite file at increasing concurrency.
+var harnessLoadCmd = &cobra.Command{
+	Use:   "load",
+	Short: "Load test models with concurrent requests",
+	Long: `The 'load' subcommand sends concurrent requests to every model and host of a suite file, one concurrency level
+at a time, cycling through the suite's scenarios. By default closed-loop workers send their next request as soon as the
+previous one is done; with --rate, requests start at a fixed rate with at most the concurrency level in flight.
+For each level it reports requests per second, aggregate generated tokens per second, TTFT and total latency
+percentiles, and the time requests waited on the server, which shows when the host runs out of parallel slots
+(OLLAMA_NUM_PARALLEL). Flags override the load section of the suite.`,
+	Example: `  gollamacli harness load --suite harness.example.yaml
+  gollamacli harness load --suite harness.example.yaml --concurrency 1,2,4 --duration 1m --ramp-up 10s
+  gollamacli harness load --suite harness.example.yaml --rate 2 --concurrency 8`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		return runHarnessLoad(cmd.Context(), harnessLoadOpts, cmd.OutOrStdout(), cmd.ErrOrStderr())
+	},
+}
+
+func init() {
+	harnessCmd.AddCommand(harnessLoadCmd)
+
+	flags := harnessLoadCmd.Flags()
+	flags.StringVarP(&harnessLoadOpts.SuitePath, "suite", "s", "", "suite file (.yaml, .yml or .json)")
+	harnessLoadCmd.MarkFlagRequired("suite")
+	flags.StringVarP(&harnessLoadOpts.ConfigPath, "config", "c", "config.json", "config file whose hosts are tested when the suite lists none")
+	flags.StringVar(&harnessLoadOpts.ResultsDir, "results-dir", "harness-results", "directory results are saved in, under load/")
+	flags.IntSliceVar(&harnessLoadOpts.Concurrency, "concurrency", nil, "concurrency levels, e.g. 1,2,4,8")
+	flags.DurationVar(&harnessLoadOpts.Duration, "duration", 0, "measured time per level (default 30s)")
+	flags.DurationVar(&harnessLoadOpts.RampUp, "ramp-up", 0, "time over which workers are started or the rate is raised; not measured")
+	flags.Float64Var(&harnessLoadOpts.Rate, "rate", 0, "requests per second; 0 runs closed-loop workers")
+}
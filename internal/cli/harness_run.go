@@ -7,16 +7,36 @@ import (
 	"github.com/mwiater/gollamacli/internal/harness"
 )
 
-// harnessRunCmd
+var runHarness = harness.Run
+
+// harnessRunOpts holds the flag values for the 'harness run' command.
+var harnessRunOpts harness.RunOptions
+
+// harnessRunCmd implements 'harness run', which benchmarks the models and
+// scenarios of a suite file.
 var harnessRunCmd = &cobra.Command{
 	Use:   "run",
-	Short: "",
-	Long:  ``,
-	Run: func(cmd *cobra.Command, args []string) {
-		harness.Run()
+	Short: "Benchmark models with the scenarios of a suite file",
+	Long: `The 'run' subcommand loads a benchmark suite from a YAML or JSON file, measures time to first token,
+total latency and token throughput for every host, model and scenario, and prints a summary.
+The full result is saved in the results directory under a run id that 'harness compare' accepts.
+When the suite defines thresholds or a baseline, failed checks are reported and the command exits non-zero.
+See harness.example.yaml for the suite format.`,
+	Example: `  gollamacli harness run --suite harness.example.yaml
+  gollamacli harness run --suite harness.example.yaml --baseline latest`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		return runHarness(cmd.Context(), harnessRunOpts, cmd.OutOrStdout(), cmd.ErrOrStderr())
 	},
 }
 
 func init() {
 	harnessCmd.AddCommand(harnessRunCmd)
+
+	harnessRunCmd.Flags().StringVarP(&harnessRunOpts.SuitePath, "suite", "s", "", "suite file (.yaml, .yml or .json)")
+	harnessRunCmd.MarkFlagRequired("suite")
+	harnessRunCmd.Flags().StringVarP(&harnessRunOpts.ConfigPath, "config", "c", "config.json", "config file whose hosts are benchmarked when the suite lists none")
+	harnessRunCmd.Flags().StringVar(&harnessRunOpts.ResultsDir, "results-dir", "harness-results", "directory results are saved in")
+	harnessRunCmd.Flags().StringVar(&harnessRunOpts.Baseline, "baseline", "", "run to check regressions against, overriding the suite's baseline run")
 }
@@ -0,0 +1,18 @@
+// cmd/gollamacli/votes.go
+package gollamacli
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// votesCmd groups the subcommands that work with the votes cast in
+// multimodel chat.
+var votesCmd = &cobra.Command{
+	Use:   "votes",
+	Short: "Work with votes cast in multimodel chat",
+	Long:  `The 'votes' command groups subcommands that analyze the preferences recorded with ctrl+b and /rank in multimodel chat.`,
+}
+
+func init() {
+	rootCmd.AddCommand(votesCmd)
+}
@@ -0,0 +1,45 @@
+// cmd/gollamacli/votes_report.go
+package gollamacli
+
+import (
+	"github.com/mwiater/gollamacli/cli"
+	"github.com/spf13/cobra"
+)
+
+var (
+	votesReport         = cli.VotesReport
+	configuredVotesFile = cli.ConfiguredVotesFile
+)
+
+// votesFile, votesConfig and votesBy hold the flag values for the 'votes
+// report' command.
+var (
+	votesFile   string
+	votesConfig string
+	votesBy     string
+)
+
+// votesReportCmd implements 'votes report', which prints win rates and Elo
+// ratings computed from the votes file.
+var votesReportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Show win rates and Elo ratings from recorded votes",
+	Long: `The 'report' subcommand reads the votes file and ranks models, or personas (model, system prompt and parameters),
+by an Elo rating computed from every pairwise preference across sessions. It also shows how often each was voted best.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		path := votesFile
+		if path == "" {
+			path = configuredVotesFile(votesConfig)
+		}
+		return votesReport(path, votesBy, cmd.OutOrStdout())
+	},
+}
+
+func init() {
+	votesCmd.AddCommand(votesReportCmd)
+
+	votesReportCmd.Flags().StringVarP(&votesFile, "file", "f", "", "votes file written by multimodel chat (default: votes_file from the config, or votes.jsonl)")
+	votesReportCmd.Flags().StringVarP(&votesConfig, "config", "c", "config.json", "config file whose votes_file is read when --file is not set")
+	votesReportCmd.Flags().StringVar(&votesBy, "by", "model", "group ratings by model or persona")
+}
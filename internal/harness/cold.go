@@ -0,0 +1,115 @@
+// harness/cold.go
+// Package: harness
+package harness
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// How long to wait for an unloaded model to leave /api/ps, and how often
+// to check.
+var (
+	unloadTimeout      = 30 * time.Second
+	unloadPollInterval = 250 * time.Millisecond
+)
+
+// coldTrial unloads the model, waits until the host no longer lists it as
+// running, and measures one request. Any failure is returned as a failed
+// row, so a model that never unloads cannot pass for a cold start.
+func coldTrial(ctx context.Context, client *http.Client, base string, model HarnessModelConfig, scenario HarnessPromptScenario) HarnessTrialResult {
+	if err := unloadModel(ctx, client, base, model.Name); err != nil {
+		return failedTrial(model, scenario, true, err)
+	}
+	if err := waitUnloaded(ctx, client, base, model.Name); err != nil {
+		return failedTrial(model, scenario, true, err)
+	}
+	tr, err := GenerateAndMeasure(ctx, client, base, model, scenario, true)
+	if err != nil {
+		return failedTrial(model, scenario, true, err)
+	}
+	return tr
+}
+
+// unloadModel asks the host to unload the model with a chat request that
+// sets keep_alive to 0, like OllamaHost.UnloadModel.
+func unloadModel(ctx context.Context, client *http.Client, base, model string) error {
+	body, _ := json.Marshal(map[string]any{"model": model, "keep_alive": 0})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, base+"/api/chat", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("could not unload %s: %w", model, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("could not unload %s: status=%d body=%s", model, resp.StatusCode, string(b))
+	}
+	return nil
+}
+
+// waitUnloaded polls /api/ps until the model is no longer running.
+func waitUnloaded(ctx context.Context, client *http.Client, base, model string) error {
+	deadline := time.Now().Add(unloadTimeout)
+	for {
+		running, err := modelRunning(ctx, client, base, model)
+		if err != nil {
+			return err
+		}
+		if !running {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("%s still loaded %s after unloading", model, unloadTimeout)
+		}
+		if !sleepCtx(ctx, unloadPollInterval) {
+			return ctx.Err()
+		}
+	}
+}
+
+// modelRunning reports whether /api/ps lists the model. A name without a
+// tag matches the "latest" tag, as in Ollama.
+func modelRunning(ctx context.Context, client *http.Client, base, model string) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, base+"/api/ps", nil)
+	if err != nil {
+		return false, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("could not list running models: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("could not list running models: status=%d", resp.StatusCode)
+	}
+
+	var ps struct {
+		Models []struct {
+			Name  string `json:"name"`
+			Model string `json:"model"`
+		} `json:"models"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&ps); err != nil {
+		return false, fmt.Errorf("could not parse running models: %w", err)
+	}
+	if !strings.Contains(model, ":") {
+		model += ":latest"
+	}
+	for _, m := range ps.Models {
+		if m.Name == model || m.Model == model {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,101 @@
+// harness/cold_test.go
+package harness
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// loadingOllama tracks whether model m is loaded: generating loads it, taking
+// loadMillis, and a keep_alive 0 chat request unloads it unless sticky.
+func loadingOllama(t *testing.T, loadMillis int64, sticky bool) *httptest.Server {
+	t.Helper()
+	var mu sync.Mutex
+	loaded := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.URL.Path {
+		case "/api/chat":
+			var req map[string]any
+			json.NewDecoder(r.Body).Decode(&req)
+			if req["model"] == "m" && req["keep_alive"] == 0.0 && !sticky {
+				loaded = false
+			}
+			fmt.Fprintln(w, `{"done": true}`)
+		case "/api/ps":
+			if loaded {
+				fmt.Fprintln(w, `{"models": [{"name": "m:latest", "model": "m:latest"}]}`)
+			} else {
+				fmt.Fprintln(w, `{"models": []}`)
+			}
+		case "/api/generate":
+			load := int64(0)
+			if !loaded {
+				load, loaded = loadMillis, true
+			}
+			fmt.Fprintln(w, `{"response": "hi", "done": false}`)
+			fmt.Fprintf(w, `{"response": "", "done": true, "eval_count": 10, "eval_duration": 1000000000, "load_duration": %d}`+"\n", load*1_000_000)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestColdTrialsUnloadFirst(t *testing.T) {
+	server := loadingOllama(t, 50, false)
+	cfg := HarnessSuiteConfig{
+		Hosts:      []HarnessHost{{URL: server.URL}},
+		Models:     []HarnessModelConfig{{Name: "m"}},
+		Scenarios:  []HarnessPromptScenario{{ID: "short", Prompt: "hello"}},
+		Trials:     2,
+		Warmup:     true,
+		ColdTrials: 3,
+	}
+
+	res, err := RunSpeedSuite(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("RunSpeedSuite: %v", err)
+	}
+	if len(res.Trials) != 5 {
+		t.Fatalf("expected 3 cold and 2 warm trials, got %+v", res.Trials)
+	}
+	for i, tr := range res.Trials {
+		cold := i < 3
+		if tr.Cold != cold || tr.failed() {
+			t.Fatalf("trial %d: expected cold=%v and success, got %+v", i, cold, tr)
+		}
+		// Every cold start loads the model; the warm-up loaded it for the warm trials.
+		if want := map[bool]int64{true: 50, false: 0}[cold]; tr.LoadMillis != want {
+			t.Errorf("trial %d: expected load %d ms, got %d", i, want, tr.LoadMillis)
+		}
+	}
+	if c := res.ModelReports[0].Cold; c == nil || c.Trials != 3 || c.LoadP50 != 50 {
+		t.Errorf("expected a cold load distribution over 3 trials, got %+v", c)
+	}
+}
+
+func TestColdTrialFailsWhenModelStaysLoaded(t *testing.T) {
+	defer func(timeout, interval time.Duration) { unloadTimeout, unloadPollInterval = timeout, interval }(unloadTimeout, unloadPollInterval)
+	unloadTimeout, unloadPollInterval = 20*time.Millisecond, 5*time.Millisecond
+
+	server := loadingOllama(t, 50, true)
+	client := newHTTPClient(time.Second)
+	model := HarnessModelConfig{Name: "m"}
+	sc := HarnessPromptScenario{ID: "short", Prompt: "hello"}
+	if _, err := GenerateAndMeasure(context.Background(), client, server.URL, model, sc, false); err != nil {
+		t.Fatal(err)
+	}
+
+	tr := coldTrial(context.Background(), client, server.URL, model, sc)
+	if !tr.Cold || !tr.failed() || !strings.Contains(tr.DoneReason, "still loaded") {
+		t.Fatalf("expected a failed cold trial, got %+v", tr)
+	}
+}
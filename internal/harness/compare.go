@@ -0,0 +1,187 @@
+// harness/compare.go
+// Package: harness
+package harness
+
+import (
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"sort"
+	"text/tabwriter"
+)
+
+// significanceLevel is the p-value below which a difference is flagged.
+const significanceLevel = 0.05
+
+// compareKey identifies the trials compared between two runs.
+type compareKey struct {
+	host, model, scenario string
+}
+
+// compareMetric is a statistic reported by Compare.
+type compareMetric struct {
+	name string
+	// sample extracts the per-trial value the statistic is computed from.
+	sample func(HarnessTrialResult) float64
+	// stat reduces the samples of a run to the reported value.
+	stat func([]float64) float64
+	// higherIsBetter marks throughput metrics.
+	higherIsBetter bool
+}
+
+var compareMetrics = []compareMetric{
+	{name: "ttft p50 ms", sample: func(t HarnessTrialResult) float64 { return float64(t.TTFTMillis) }, stat: p50},
+	{name: "ttft p95 ms", sample: func(t HarnessTrialResult) float64 { return float64(t.TTFTMillis) }, stat: p95},
+	{name: "total p50 ms", sample: func(t HarnessTrialResult) float64 { return float64(t.TotalMillis) }, stat: p50},
+	{name: "total p95 ms", sample: func(t HarnessTrialResult) float64 { return float64(t.TotalMillis) }, stat: p95},
+	{name: "gen tok/s", sample: func(t HarnessTrialResult) float64 { return t.GenTokensPerSec }, stat: mean, higherIsBetter: true},
+}
+
+func p50(v []float64) float64 { return simpleQuantile(v, 0.50) }
+func p95(v []float64) float64 { return simpleQuantile(v, 0.95) }
+func p99(v []float64) float64 { return simpleQuantile(v, 0.99) }
+func mean(v []float64) float64 {
+	m, _ := meanStd(v)
+	return m
+}
+
+// Compare writes the per host, model and scenario differences between runs a
+// and b to out. Only successful warm trials are compared. Each difference is
+// tested with a bootstrap of the difference in means of the trial samples and
+// flagged when p < 0.05.
+func Compare(a, b HarnessSuiteResult, out io.Writer) error {
+	groupsA, groupsB := groupTrials(a.Trials), groupTrials(b.Trials)
+	keys := make([]compareKey, 0, len(groupsA))
+	for k := range groupsA {
+		keys = append(keys, k)
+	}
+	for k := range groupsB {
+		if _, ok := groupsA[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sortKeys(keys)
+
+	fmt.Fprintf(out, "A: %s (%s)\nB: %s (%s)\n\n", a.RunID, a.GeneratedAt.Format("2006-01-02 15:04:05"), b.RunID, b.GeneratedAt.Format("2006-01-02 15:04:05"))
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "HOST\tMODEL\tSCENARIO\tMETRIC\tA\tB\tDELTA\tP\tCHANGE")
+	for _, k := range keys {
+		rowsA, rowsB := groupsA[k], groupsB[k]
+		if len(rowsA) == 0 || len(rowsB) == 0 {
+			only := "B"
+			if len(rowsB) == 0 {
+				only = "A"
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t(only in %s)\t\t\t\t\t\n", k.host, k.model, k.scenario, only)
+			continue
+		}
+		for _, m := range compareMetrics {
+			sa, sb := samples(rowsA, m.sample), samples(rowsB, m.sample)
+			va, vb := m.stat(sa), m.stat(sb)
+			p := bootstrapP(sa, sb)
+			flag := ""
+			if p < significanceLevel {
+				flag = "worse"
+				if (vb > va) == m.higherIsBetter {
+					flag = "better"
+				}
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%.1f\t%.1f\t%s\t%.3f\t%s\n", k.host, k.model, k.scenario, m.name, va, vb, formatDelta(va, vb), p, flag)
+		}
+	}
+	return tw.Flush()
+}
+
+// CompareRuns loads two saved runs, see LoadResult, and compares them.
+func CompareRuns(resultsDir, refA, refB string, out io.Writer) error {
+	a, err := LoadResult(resultsDir, refA)
+	if err != nil {
+		return err
+	}
+	b, err := LoadResult(resultsDir, refB)
+	if err != nil {
+		return err
+	}
+	return Compare(a, b, out)
+}
+
+// sortKeys orders keys by host, model and scenario.
+func sortKeys(keys []compareKey) {
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].host != keys[j].host {
+			return keys[i].host < keys[j].host
+		}
+		if keys[i].model != keys[j].model {
+			return keys[i].model < keys[j].model
+		}
+		return keys[i].scenario < keys[j].scenario
+	})
+}
+
+// groupTrials groups the successful warm trials by host, model and scenario.
+func groupTrials(trials []HarnessTrialResult) map[compareKey][]HarnessTrialResult {
+	groups := map[compareKey][]HarnessTrialResult{}
+	for _, t := range trials {
+		if t.Cold || t.failed() {
+			continue
+		}
+		k := compareKey{host: t.Host, model: t.ModelName, scenario: t.ScenarioID}
+		groups[k] = append(groups[k], t)
+	}
+	return groups
+}
+
+// samples extracts one value per trial.
+func samples(rows []HarnessTrialResult, f func(HarnessTrialResult) float64) []float64 {
+	out := make([]float64, len(rows))
+	for i, r := range rows {
+		out[i] = f(r)
+	}
+	return out
+}
+
+// formatDelta renders the relative change from a to b.
+func formatDelta(a, b float64) string {
+	if a == 0 {
+		if b == 0 {
+			return "0.0%"
+		}
+		return "n/a"
+	}
+	return fmt.Sprintf("%+.1f%%", (b-a)/a*100)
+}
+
+// bootstrapResamples is the number of resamples bootstrapP draws.
+const bootstrapResamples = 10000
+
+// bootstrapP returns the two-sided p-value of the difference in means of b
+// and a from a percentile bootstrap: twice the share of resampled
+// differences on the less common side of zero. p < 0.05 means the 95%
+// interval of the difference excludes zero. The resamples use a fixed seed,
+// so a report is reproducible. With fewer than three samples on either side
+// it returns 1.
+func bootstrapP(a, b []float64) float64 {
+	if len(a) < 3 || len(b) < 3 {
+		return 1
+	}
+	rng := rand.New(rand.NewPCG(1, 2))
+	resampleMean := func(v []float64) float64 {
+		sum := 0.0
+		for range v {
+			sum += v[rng.IntN(len(v))]
+		}
+		return sum / float64(len(v))
+	}
+
+	below, above := 0, 0
+	for i := 0; i < bootstrapResamples; i++ {
+		d := resampleMean(b) - resampleMean(a)
+		if d <= 0 {
+			below++
+		}
+		if d >= 0 {
+			above++
+		}
+	}
+	return min(2*float64(min(below, above))/bootstrapResamples, 1)
+}
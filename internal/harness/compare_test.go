@@ -0,0 +1,119 @@
+// harness/compare_test.go
+package harness
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBootstrapP(t *testing.T) {
+	if p := bootstrapP([]float64{1, 2, 3, 4, 5}, []float64{6, 7, 8, 9, 10}); p >= 0.05 {
+		t.Errorf("expected separated samples to be significant, got %.4f", p)
+	}
+	// Three trials per side, as in harness.example.yaml, are enough when the
+	// runs do not overlap.
+	if p := bootstrapP([]float64{100, 104, 108}, []float64{120, 125, 131}); p >= 0.05 {
+		t.Errorf("expected separated samples of three to be significant, got %.4f", p)
+	}
+	if p := bootstrapP([]float64{100, 120, 140}, []float64{110, 130, 150}); p < 0.05 {
+		t.Errorf("expected overlapping samples of three not to be significant, got %.4f", p)
+	}
+	if p := bootstrapP([]float64{1, 2, 3, 4}, []float64{1, 2, 3, 4}); p < 0.9 {
+		t.Errorf("expected p ≈ 1 for identical samples, got %.4f", p)
+	}
+	if p := bootstrapP([]float64{1, 1, 1}, []float64{1, 1, 1}); p != 1 {
+		t.Errorf("expected p = 1 when every value is tied, got %.4f", p)
+	}
+	if p := bootstrapP([]float64{1, 2}, []float64{5, 6, 7}); p != 1 {
+		t.Errorf("expected p = 1 for too few samples, got %.4f", p)
+	}
+	if p, q := bootstrapP([]float64{1, 5, 9}, []float64{4, 8, 12}), bootstrapP([]float64{1, 5, 9}, []float64{4, 8, 12}); p != q {
+		t.Errorf("expected reproducible p-values, got %.4f and %.4f", p, q)
+	}
+}
+
+func TestCompareFlagsThreeTrials(t *testing.T) {
+	a := HarnessSuiteResult{Trials: trialsWith(3, 100, 20)}
+	b := HarnessSuiteResult{Trials: trialsWith(3, 100, 30)}
+	var out bytes.Buffer
+	if err := Compare(a, b, &out); err != nil {
+		t.Fatalf("Compare: %v", err)
+	}
+	var tps string
+	for _, line := range strings.Split(out.String(), "\n") {
+		if strings.Contains(line, "gen tok/s") {
+			tps = line
+		}
+	}
+	if !strings.HasSuffix(strings.TrimSpace(tps), "better") {
+		t.Errorf("expected three clearly faster trials to be flagged, got %q", tps)
+	}
+}
+
+// trialsWith returns n successful warm trials with the given TTFT and gen TPS
+// offsets, plus a cold and a failed trial that comparisons must ignore.
+func trialsWith(n int, ttft int64, tps float64) []HarnessTrialResult {
+	rows := []HarnessTrialResult{
+		{Host: "h", ModelName: "m", ScenarioID: "s", Cold: true, TTFTMillis: 5000},
+		{Host: "h", ModelName: "m", ScenarioID: "s", DoneReason: "error: boom"},
+	}
+	for i := 0; i < n; i++ {
+		rows = append(rows, HarnessTrialResult{
+			Host: "h", ModelName: "m", ScenarioID: "s",
+			TTFTMillis: ttft + int64(i), TotalMillis: 1000 + int64(i), GenTokensPerSec: tps + float64(i),
+		})
+	}
+	return rows
+}
+
+func TestSaveLoadAndCompare(t *testing.T) {
+	dir := t.TempDir()
+	a := HarnessSuiteResult{Trials: trialsWith(6, 100, 20), GeneratedAt: time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)}
+	b := HarnessSuiteResult{Trials: trialsWith(6, 100, 40), GeneratedAt: time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)}
+	b.Trials = append(b.Trials, HarnessTrialResult{Host: "h", ModelName: "new", ScenarioID: "s"})
+
+	pathA, err := SaveResult(dir, &a)
+	if err != nil {
+		t.Fatalf("SaveResult: %v", err)
+	}
+	if len(a.RunID) != 7 || !strings.HasSuffix(pathA, "20260101-100000-"+a.RunID+".json") {
+		t.Fatalf("unexpected run id %q or path %s", a.RunID, pathA)
+	}
+	if _, err := SaveResult(dir, &b); err != nil {
+		t.Fatalf("SaveResult: %v", err)
+	}
+
+	latest, err := LoadResult(dir, "latest")
+	if err != nil || latest.RunID != b.RunID {
+		t.Fatalf("expected latest to be run B, got %q (%v)", latest.RunID, err)
+	}
+	if _, err := LoadResult(dir, "zzz"); err == nil {
+		t.Fatal("expected an unknown run id to be rejected")
+	}
+
+	var out bytes.Buffer
+	if err := CompareRuns(dir, a.RunID[:5], "latest", &out); err != nil {
+		t.Fatalf("CompareRuns: %v", err)
+	}
+	report := out.String()
+	var tps, ttft string
+	for _, line := range strings.Split(report, "\n") {
+		switch {
+		case strings.Contains(line, "gen tok/s"):
+			tps = line
+		case strings.Contains(line, "ttft p50"):
+			ttft = line
+		}
+	}
+	if !strings.Contains(tps, "+88.9%") || !strings.HasSuffix(strings.TrimSpace(tps), "better") {
+		t.Errorf("expected a significant throughput gain, got %q", tps)
+	}
+	if !strings.Contains(ttft, "+0.0%") || strings.Contains(ttft, "better") || strings.Contains(ttft, "worse") {
+		t.Errorf("expected an unchanged TTFT without a flag, got %q", ttft)
+	}
+	if !strings.Contains(report, "(only in B)") {
+		t.Errorf("expected the model only in B to be listed, got:\n%s", report)
+	}
+}
@@ -0,0 +1,209 @@
+// harness/gates.go
+// Package: harness
+package harness
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"text/tabwriter"
+)
+
+// gateCheck is the outcome of one limit applied to one host, model and
+// scenario combination.
+type gateCheck struct {
+	key    compareKey
+	name   string // e.g. "ttft p95 ms"
+	value  float64
+	limit  string // e.g. "<= 500.0"
+	failed bool
+	// note replaces the value when there is none to show, e.g. "no
+	// successful trials".
+	note string
+}
+
+// gateStats are the statistics gates are checked against. ok is the number
+// of successful trials the latency and throughput figures come from.
+type gateStats struct {
+	ttftP95, totalP95, genTPS, errorRate float64
+	ok                                   int
+}
+
+// collectGateStats computes gate statistics per host, model and scenario
+// from the warm trials. Failed trials only count towards the error rate.
+func collectGateStats(trials []HarnessTrialResult) map[compareKey]gateStats {
+	type counts struct {
+		rows   []HarnessTrialResult
+		failed int
+		total  int
+	}
+	groups := map[compareKey]*counts{}
+	for _, t := range trials {
+		if t.Cold {
+			continue
+		}
+		k := compareKey{host: t.Host, model: t.ModelName, scenario: t.ScenarioID}
+		c, ok := groups[k]
+		if !ok {
+			c = &counts{}
+			groups[k] = c
+		}
+		c.total++
+		if t.failed() {
+			c.failed++
+			continue
+		}
+		c.rows = append(c.rows, t)
+	}
+
+	stats := map[compareKey]gateStats{}
+	for k, c := range groups {
+		ttft := samples(c.rows, func(t HarnessTrialResult) float64 { return float64(t.TTFTMillis) })
+		total := samples(c.rows, func(t HarnessTrialResult) float64 { return float64(t.TotalMillis) })
+		tps := samples(c.rows, func(t HarnessTrialResult) float64 { return t.GenTokensPerSec })
+		stats[k] = gateStats{
+			ttftP95:   p95(ttft),
+			totalP95:  p95(total),
+			genTPS:    mean(tps),
+			errorRate: float64(c.failed) / float64(c.total),
+			ok:        len(c.rows),
+		}
+	}
+	return stats
+}
+
+// matches reports whether the threshold applies to k. Selectors may use
+// path.Match patterns such as "llama*".
+func (th HarnessThreshold) matches(k compareKey) bool {
+	for _, sel := range [][2]string{{th.Host, k.host}, {th.Model, k.model}, {th.Scenario, k.scenario}} {
+		if sel[0] == "" {
+			continue
+		}
+		if ok, _ := path.Match(sel[0], sel[1]); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// selector renders a threshold selector for the report; empty matches all.
+func selector(s string) string {
+	if s == "" {
+		return "*"
+	}
+	return s
+}
+
+// checkGates applies the suite's thresholds to res and, when baseline is not
+// nil, the allowed regression against it. Thresholds that match no results
+// and result groups missing from the baseline count as failed checks.
+func checkGates(cfg HarnessSuiteConfig, res HarnessSuiteResult, baseline *HarnessSuiteResult) []gateCheck {
+	stats := collectGateStats(res.Trials)
+	keys := make([]compareKey, 0, len(stats))
+	for k := range stats {
+		keys = append(keys, k)
+	}
+	sortKeys(keys)
+
+	// Latency and throughput checks fail when every trial of the group
+	// failed, since there is nothing to measure.
+	var checks []gateCheck
+	atMost := func(k compareKey, name string, value, limit float64) {
+		checks = append(checks, gateCheck{key: k, name: name, value: value, limit: fmt.Sprintf("<= %.1f", limit), failed: value > limit})
+	}
+	atLeast := func(k compareKey, name string, value, limit float64) {
+		checks = append(checks, gateCheck{key: k, name: name, value: value, limit: fmt.Sprintf(">= %.1f", limit), failed: value < limit})
+	}
+	measured := func(s gateStats, check func()) {
+		check()
+		if s.ok == 0 {
+			checks[len(checks)-1].failed = true
+			checks[len(checks)-1].note = "no successful trials"
+		}
+	}
+	missing := func(k compareKey, name, note string) {
+		checks = append(checks, gateCheck{key: k, name: name, note: note, failed: true})
+	}
+
+	matched := make([]bool, len(cfg.Thresholds))
+	for _, k := range keys {
+		s := stats[k]
+		for i, th := range cfg.Thresholds {
+			if !th.matches(k) {
+				continue
+			}
+			matched[i] = true
+			if th.MaxTTFTP95 > 0 {
+				measured(s, func() { atMost(k, "ttft p95 ms", s.ttftP95, th.MaxTTFTP95) })
+			}
+			if th.MaxTotalP95 > 0 {
+				measured(s, func() { atMost(k, "total p95 ms", s.totalP95, th.MaxTotalP95) })
+			}
+			if th.MinGenTPS > 0 {
+				measured(s, func() { atLeast(k, "gen tok/s", s.genTPS, th.MinGenTPS) })
+			}
+			if th.MaxErrorRate != nil {
+				atMost(k, "error rate", s.errorRate, *th.MaxErrorRate)
+			}
+		}
+	}
+	// A threshold that matches nothing, such as one with a misspelled
+	// model, would otherwise pass silently.
+	for i, th := range cfg.Thresholds {
+		if !matched[i] {
+			missing(compareKey{host: selector(th.Host), model: selector(th.Model), scenario: selector(th.Scenario)}, "threshold", "matches no results")
+		}
+	}
+
+	if baseline != nil && cfg.Baseline != nil {
+		base := collectGateStats(baseline.Trials)
+		allowed := 1 + cfg.Baseline.MaxRegressionPct/100
+		for _, k := range keys {
+			// A baseline group without successful trials has nothing to
+			// compare against.
+			b, ok := base[k]
+			if !ok {
+				missing(k, "baseline", "not in baseline")
+				continue
+			}
+			if b.ok == 0 {
+				continue
+			}
+			s := stats[k]
+			measured(s, func() { atMost(k, "ttft p95 ms vs baseline", s.ttftP95, b.ttftP95*allowed) })
+			measured(s, func() { atMost(k, "total p95 ms vs baseline", s.totalP95, b.totalP95*allowed) })
+			measured(s, func() { atLeast(k, "gen tok/s vs baseline", s.genTPS, b.genTPS/allowed) })
+		}
+	}
+	return checks
+}
+
+// writeGateReport writes the failed checks to out and returns how many
+// checks failed.
+func writeGateReport(out io.Writer, checks []gateCheck) int {
+	failed := 0
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	for _, c := range checks {
+		if !c.failed {
+			continue
+		}
+		if failed == 0 {
+			fmt.Fprintln(tw, "HOST\tMODEL\tSCENARIO\tCHECK\tVALUE\tLIMIT")
+		}
+		failed++
+		value := fmt.Sprintf("%.2f", c.value)
+		if c.note != "" {
+			value = c.note
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", c.key.host, c.key.model, c.key.scenario, c.name, value, c.limit)
+	}
+	tw.Flush()
+
+	switch {
+	case failed > 0:
+		fmt.Fprintf(out, "\nGates: %d of %d checks FAILED\n", failed, len(checks))
+	case len(checks) > 0:
+		fmt.Fprintf(out, "Gates: all %d checks passed\n", len(checks))
+	}
+	return failed
+}
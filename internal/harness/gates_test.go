@@ -0,0 +1,145 @@
+// harness/gates_test.go
+package harness
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+// gateSuite writes a suite named name that benchmarks url with the given
+// extra keys.
+func gateSuite(t *testing.T, dir, name, url, extra string) string {
+	t.Helper()
+	return writeFile(t, dir, name+".yaml", fmt.Sprintf(`
+hosts:
+  - name: local
+    url: %s
+models:
+  - name: m
+scenarios:
+  - id: short
+    prompt: hello
+trials: 3
+warmup: false
+%s`, url, extra))
+}
+
+func TestRunThresholds(t *testing.T) {
+	server := fakeOllama(t, 20)
+	dir := t.TempDir()
+
+	pass := gateSuite(t, dir, "pass", server.URL, `
+thresholds:
+  - model: m
+    min_gen_tps: 10
+    max_error_rate: 0
+`)
+	var out bytes.Buffer
+	if err := Run(context.Background(), RunOptions{SuitePath: pass}, &out, io.Discard); err != nil {
+		t.Fatalf("expected gates to pass, got %v\n%s", err, out.String())
+	}
+	if !strings.Contains(out.String(), "Gates: all 2 checks passed") {
+		t.Errorf("expected passed gates in output, got:\n%s", out.String())
+	}
+
+	fail := gateSuite(t, dir, "fail", server.URL, `
+thresholds:
+  - scenario: sh*
+    min_gen_tps: 30
+`)
+	out.Reset()
+	err := Run(context.Background(), RunOptions{SuitePath: fail}, &out, io.Discard)
+	if err == nil || err.Error() != "1 gate check(s) failed" {
+		t.Fatalf("expected a failed gate, got %v", err)
+	}
+	for _, want := range []string{"gen tok/s", "20.00", ">= 30.0", "Gates: 1 of 1 checks FAILED"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected %q in report, got:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestRunBaselineRegression(t *testing.T) {
+	dir := t.TempDir()
+	results := t.TempDir()
+
+	fast := gateSuite(t, dir, "fast", fakeOllama(t, 40).URL, "")
+	if err := Run(context.Background(), RunOptions{SuitePath: fast, ResultsDir: results}, io.Discard, io.Discard); err != nil {
+		t.Fatalf("baseline run: %v", err)
+	}
+
+	slow := gateSuite(t, dir, "slow", fakeOllama(t, 30).URL, `
+baseline:
+  run: latest
+  max_regression_pct: 10
+`)
+	var out bytes.Buffer
+	err := Run(context.Background(), RunOptions{SuitePath: slow, ResultsDir: results}, &out, io.Discard)
+	if err == nil {
+		t.Fatalf("expected a gen TPS regression to fail, got:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "gen tok/s vs baseline") {
+		t.Errorf("expected the regression in the report, got:\n%s", out.String())
+	}
+
+	// The suite must define the allowed regression for --baseline to apply.
+	err = Run(context.Background(), RunOptions{SuitePath: fast, ResultsDir: results, Baseline: "latest"}, io.Discard, io.Discard)
+	if err == nil || !strings.Contains(err.Error(), "max_regression_pct") {
+		t.Errorf("expected --baseline without a suite baseline to fail, got %v", err)
+	}
+}
+
+func TestGatesFailWithoutSuccessfulTrials(t *testing.T) {
+	trials := []HarnessTrialResult{
+		{Host: "local", ModelName: "m", ScenarioID: "short", DoneReason: "error: connection refused"},
+		{Host: "local", ModelName: "m", ScenarioID: "short", DoneReason: "error: connection refused"},
+	}
+	baseline := &HarnessSuiteResult{Trials: []HarnessTrialResult{
+		{Host: "local", ModelName: "m", ScenarioID: "short", DoneReason: "stop", TTFTMillis: 100, TotalMillis: 500, GenTokensPerSec: 20},
+	}}
+	cfg := HarnessSuiteConfig{
+		Thresholds: []HarnessThreshold{{MaxTTFTP95: 500, MaxTotalP95: 1000, MinGenTPS: 0.1}},
+		Baseline:   &HarnessBaseline{MaxRegressionPct: 10},
+	}
+
+	checks := checkGates(cfg, HarnessSuiteResult{Trials: trials}, baseline)
+	if len(checks) != 6 {
+		t.Fatalf("expected 6 checks, got %d", len(checks))
+	}
+	for _, c := range checks {
+		if !c.failed {
+			t.Errorf("expected %q to fail without successful trials", c.name)
+		}
+	}
+	var out bytes.Buffer
+	if n := writeGateReport(&out, checks); n != 6 || !strings.Contains(out.String(), "no successful trials") {
+		t.Errorf("expected 6 failures reported as unmeasured, got %d:\n%s", n, out.String())
+	}
+}
+
+func TestGatesFailUnmatchedThresholdsAndBaselines(t *testing.T) {
+	trials := []HarnessTrialResult{
+		{Host: "local", ModelName: "llama3", ScenarioID: "short", DoneReason: "stop", TTFTMillis: 100, GenTokensPerSec: 20},
+	}
+	baseline := &HarnessSuiteResult{Trials: []HarnessTrialResult{
+		{Host: "old", ModelName: "llama3", ScenarioID: "short", DoneReason: "stop", TTFTMillis: 100, GenTokensPerSec: 20},
+	}}
+	cfg := HarnessSuiteConfig{
+		Thresholds: []HarnessThreshold{{Model: "llama*", MinGenTPS: 10}, {Model: "lama3*", MinGenTPS: 10}},
+		Baseline:   &HarnessBaseline{MaxRegressionPct: 10},
+	}
+
+	var out bytes.Buffer
+	if n := writeGateReport(&out, checkGates(cfg, HarnessSuiteResult{Trials: trials}, baseline)); n != 2 {
+		t.Fatalf("expected the unmatched threshold and the missing baseline to fail, got %d:\n%s", n, out.String())
+	}
+	for _, want := range []string{"lama3*", "matches no results", "not in baseline"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected %q in report, got:\n%s", want, out.String())
+		}
+	}
+}
@@ -1,87 +1,232 @@
+// harness/harness.go
+// Package: harness
 package harness
 
 import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
 	"time"
-
-	"github.com/k0kubun/pp"
 )
 
-//"stablelm-zephyr:3b",
-//"granite3.3:2b",
-//"gemma3n:e2b",
-//"deepseek-r1:1.5b",
-//"llama3.2:1b",
-//"granite3.1-moe:1b",
-//"dolphin-phi:2.7b",
-//"qwen3:1.7b"
-
-func Run() {
-	cfg := HarnessSuiteConfig{
-		BaseURL: "https://o-udoo01.0nezer0.com",
-		Models: []HarnessModelConfig{
-			{
-				Name: "gemma3n:e2b",
-				Options: map[string]any{
-					"temperature": 0.0,
-					"top_p":       1.0,
-					"top_k":       1,
-					"num_predict": 256,
-					"stop":        []string{"\n\n"},
-				},
-			},
-			{
-				Name: "granite3.1-moe:1b",
-				Options: map[string]any{
-					"temperature": 0.0,
-					"top_p":       1.0,
-					"top_k":       1,
-					"num_predict": 256,
-					"stop":        []string{"\n\n"},
-				},
-			},
-			{
-				Name: "llama3.2:1b",
-				Options: map[string]any{
-					"temperature": 0.0,
-					"top_p":       1.0,
-					"top_k":       1,
-					"num_predict": 256,
-					"stop":        []string{"\n\n"},
-				},
-			},
-		},
-		Scenarios: []HarnessPromptScenario{
-			{ID: "short", Description: "≈128 chars", Prompt: MakeFillerPrompt(128)},
-			//{ID: "medium", Description: "≈2048 chars", Prompt: MakeFillerPrompt(2048)},
-			//{ID: "long", Description: "≈4096 chars", Prompt: MakeFillerPrompt(4096)},
-		},
-		Trials:         3,
-		Warmup:         true,
-		IncludeCold:    true,
-		RequestTimeout: 2 * time.Minute,
-	}
-
-	pp.Println(cfg)
-
-	res, err := RunSpeedSuite(context.Background(), cfg)
+// RunOptions configures Run.
+type RunOptions struct {
+	// SuitePath is the suite file, see LoadSuite.
+	SuitePath string
+	// ConfigPath is the gollamacli config whose hosts are used when the
+	// suite lists none.
+	ConfigPath string
+	// ResultsDir is where the result is saved; empty skips saving.
+	ResultsDir string
+	// Baseline overrides the run named by the suite's baseline; see
+	// LoadResult.
+	Baseline string
+}
+
+// Run loads and runs a suite, saves the result under opts.ResultsDir and
+// writes a concise summary to out. Progress goes to errOut. When the suite
+// defines thresholds or a baseline, it reports the failed checks and returns
+// an error if any failed.
+func Run(ctx context.Context, opts RunOptions, out, errOut io.Writer) error {
+	cfg, err := LoadSuite(opts.SuitePath)
+	if err != nil {
+		return err
+	}
+	if len(cfg.Hosts) == 0 {
+		if cfg.Hosts, err = configHosts(opts.ConfigPath); err != nil {
+			return err
+		}
+	}
+	if opts.Baseline != "" {
+		if cfg.Baseline == nil {
+			return fmt.Errorf("--baseline needs a baseline with max_regression_pct in %s", opts.SuitePath)
+		}
+		cfg.Baseline.Run = opts.Baseline
+	}
+
+	// Load the baseline before this run is saved so "latest" means the
+	// previous run.
+	var baseline *HarnessSuiteResult
+	if cfg.Baseline != nil {
+		b, err := LoadResult(opts.ResultsDir, cfg.Baseline.Run)
+		if err != nil {
+			return fmt.Errorf("baseline: %w", err)
+		}
+		baseline = &b
+	}
+
+	cfg.Progress = errOut
+	res, err := RunSpeedSuite(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	writeSummary(out, res.ModelReports)
+
+	if opts.ResultsDir != "" {
+		path, err := SaveResult(opts.ResultsDir, &res)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Saved run %s to %s\n", res.RunID, path)
+	}
+
+	if len(cfg.Thresholds) == 0 && baseline == nil {
+		return nil
+	}
+	if baseline != nil {
+		fmt.Fprintf(out, "Baseline: run %s, max regression %.1f%%\n", baseline.RunID, cfg.Baseline.MaxRegressionPct)
+	}
+	if failed := writeGateReport(out, checkGates(cfg, res, baseline)); failed > 0 {
+		return fmt.Errorf("%d gate check(s) failed", failed)
+	}
+	return nil
+}
+
+// writeSummary writes the warm and cold statistics of every model, followed
+// by a table of the warm statistics per scenario.
+func writeSummary(out io.Writer, reports []HarnessModelSummary) {
+	for _, m := range reports {
+		fmt.Fprintf(out, "MODEL: %s @ %s\n", m.ModelName, m.Host)
+		writeStats(out, "Warm", m.HarnessStats)
+		if m.Cold != nil {
+			writeStats(out, "Cold", *m.Cold)
+		}
+		if len(m.Scenarios) > 1 {
+			tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(tw, "  SCENARIO\tTRIALS\tERRORS\tTTFT p50/p95 ms\tTOTAL p50/p95 ms\tGEN TOK/S\tPROMPT TOK/S")
+			for _, s := range m.Scenarios {
+				fmt.Fprintf(tw, "  %s\t%d\t%d\t%.1f / %.1f\t%.1f / %.1f\t%.2f\t%.2f\n",
+					s.ScenarioID, s.Trials, s.Errors, s.TTFTP50, s.TTFTP95, s.TotalP50, s.TotalP95, s.GenTPSMean, s.PromptTPSMean)
+			}
+			tw.Flush()
+		}
+		fmt.Fprintln(out)
+	}
+}
+
+// writeStats writes one group of statistics under label.
+func writeStats(out io.Writer, label string, st HarnessStats) {
+	fmt.Fprintf(out, "  %s: %d trials, %d errors (%.1f%%)\n", label, st.Trials, st.Errors, st.ErrorRate*100)
+	if st.Errors == st.Trials {
+		return
+	}
+	fmt.Fprintf(out, "    TTFT  p50/p95/p99: %.1f / %.1f / %.1f ms (min %.1f, max %.1f, CV %.1f%%)\n", st.TTFTP50, st.TTFTP95, st.TTFTP99, st.TTFTMin, st.TTFTMax, st.TTFTCV*100)
+	fmt.Fprintf(out, "    TOTAL p50/p95/p99: %.1f / %.1f / %.1f ms (min %.1f, max %.1f, CV %.1f%%)\n", st.TotalP50, st.TotalP95, st.TotalP99, st.TotalMin, st.TotalMax, st.TotalCV*100)
+	fmt.Fprintf(out, "    Gen TPS mean±std: %.2f ± %.2f tok/s (CV %.1f%%)\n", st.GenTPSMean, st.GenTPSStd, st.GenTPSCV*100)
+	fmt.Fprintf(out, "    Prompt TPS mean±std: %.2f ± %.2f tok/s\n", st.PromptTPSMean, st.PromptTPSStd)
+	fmt.Fprintf(out, "    Load p50/p95/max: %.1f / %.1f / %.1f ms (mean %.1f)\n", st.LoadP50, st.LoadP95, st.LoadMax, st.LoadMean)
+}
+
+// LoadOptions configures RunLoad. Set fields override the load section of
+// the suite.
+type LoadOptions struct {
+	RunOptions
+	Concurrency []int
+	Duration    time.Duration
+	RampUp      time.Duration
+	Rate        float64
+}
+
+// RunLoad loads a suite, load tests its models, saves the result under
+// opts.ResultsDir and writes a report per host and model to out. Progress
+// goes to errOut.
+func RunLoad(ctx context.Context, opts LoadOptions, out, errOut io.Writer) error {
+	cfg, err := LoadSuite(opts.SuitePath)
 	if err != nil {
-		panic(err)
+		return err
+	}
+	if len(cfg.Hosts) == 0 {
+		if cfg.Hosts, err = configHosts(opts.ConfigPath); err != nil {
+			return err
+		}
+	}
+	if cfg.Load == nil {
+		cfg.Load = &HarnessLoadConfig{}
+	}
+	if len(opts.Concurrency) > 0 {
+		cfg.Load.Concurrency = opts.Concurrency
+	}
+	if opts.Duration != 0 {
+		cfg.Load.Duration = opts.Duration
+	}
+	if opts.RampUp != 0 {
+		cfg.Load.RampUp = opts.RampUp
+	}
+	if opts.Rate != 0 {
+		cfg.Load.Rate = opts.Rate
 	}
 
-	fmt.Println("???")
+	cfg.Progress = errOut
+	res, err := RunLoadSuite(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	writeLoadReport(out, res)
 
-	// Print a concise summary
-	for _, m := range res.ModelReports {
-		fmt.Printf("MODEL: %s\n", m.ModelName)
-		fmt.Printf("  TTFT  p50/p95: %.1f / %.1f ms\n", m.TTFTP50, m.TTFTP95)
-		fmt.Printf("  TOTAL p50/p95: %.1f / %.1f ms\n", m.TotalP50, m.TotalP95)
-		fmt.Printf("  Gen TPS mean±std: %.2f ± %.2f tok/s\n\n", m.GenTPSMean, m.GenTPSStd)
+	if opts.ResultsDir != "" {
+		path, err := SaveLoadResult(opts.ResultsDir, res)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Saved load test to %s\n", path)
 	}
+	return nil
+}
 
-	// Or persist to JSON
-	b, _ := json.MarshalIndent(res, "", "  ")
-	fmt.Println(string(b))
+// writeLoadReport writes one table of levels per host and model.
+func writeLoadReport(out io.Writer, res HarnessLoadResult) {
+	mode := "closed-loop workers"
+	if r := res.Config.Load.Rate; r > 0 {
+		mode = fmt.Sprintf("fixed rate %.1f req/s", r)
+	}
+	fmt.Fprintf(out, "Load test: %s, %s per level\n\n", mode, res.Config.Load.Duration)
+
+	for start := 0; start < len(res.Levels); {
+		end := start
+		for end < len(res.Levels) && res.Levels[end].Host == res.Levels[start].Host && res.Levels[end].ModelName == res.Levels[start].ModelName {
+			end++
+		}
+		levels := res.Levels[start:end]
+		start = end
+
+		fmt.Fprintf(out, "MODEL: %s @ %s\n", levels[0].ModelName, levels[0].Host)
+		tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "CONC\tREQS\tERRORS\tSKIPPED\tREQ/S\tTOK/S\tTTFT p50/p95/p99 ms\tTOTAL p50/p95/p99 ms\tQUEUE p50/p95 ms")
+		for _, l := range levels {
+			fmt.Fprintf(tw, "%d\t%d\t%d\t%d\t%.2f\t%.1f\t%.0f / %.0f / %.0f\t%.0f / %.0f / %.0f\t%.0f / %.0f\n",
+				l.Concurrency, l.Requests, l.Errors, l.Skipped, l.RequestsPerSec, l.GenTokensPerSec,
+				l.TTFTP50, l.TTFTP95, l.TTFTP99, l.TotalP50, l.TotalP95, l.TotalP99, l.QueueP50, l.QueueP95)
+		}
+		tw.Flush()
+		if n, ok := parallelSlots(levels); ok {
+			fmt.Fprintf(out, "  Requests queue above concurrency %d; the host likely serves %d in parallel (OLLAMA_NUM_PARALLEL).\n", n, n)
+		}
+		fmt.Fprintln(out)
+	}
+}
+
+// configHosts reads the hosts of the gollamacli config file at path.
+func configHosts(path string) ([]HarnessHost, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("suite has no hosts and the config could not be read: %w", err)
+	}
+	var config struct {
+		Hosts []HarnessHost `json:"hosts"`
+	}
+	if err := json.Unmarshal(b, &config); err != nil {
+		return nil, fmt.Errorf("could not parse config JSON: %w", err)
+	}
+	if len(config.Hosts) == 0 {
+		return nil, fmt.Errorf("suite has no hosts and %s defines none", path)
+	}
+	for i := range config.Hosts {
+		config.Hosts[i].URL = strings.TrimSuffix(config.Hosts[i].URL, "/")
+	}
+	return config.Hosts, nil
 }
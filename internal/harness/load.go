@@ -0,0 +1,250 @@
+// harness/load.go
+// Package: harness
+package harness
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Load test defaults.
+var (
+	defaultConcurrency  = []int{1, 2, 4, 8}
+	defaultLoadDuration = 30 * time.Second
+)
+
+// loadSample is one request sent during a load test.
+type loadSample struct {
+	start, end time.Time
+	trial      HarnessTrialResult
+	err        error
+}
+
+// RunLoadSuite sends concurrent requests to every model on every host, one
+// concurrency level at a time, and reports throughput, latency percentiles
+// and queue wait per level. Scenarios are sent in turn. Models are tested one
+// after the other; up to HostParallelism hosts run at a time.
+func RunLoadSuite(ctx context.Context, cfg HarnessSuiteConfig) (HarnessLoadResult, error) {
+	if err := prepareSuite(&cfg); err != nil {
+		return HarnessLoadResult{}, err
+	}
+	load := HarnessLoadConfig{}
+	if cfg.Load != nil {
+		load = *cfg.Load
+	}
+	if len(load.Concurrency) == 0 {
+		load.Concurrency = defaultConcurrency
+	}
+	if load.Duration == 0 {
+		load.Duration = defaultLoadDuration
+	}
+	if err := load.validate(); err != nil {
+		return HarnessLoadResult{}, err
+	}
+	cfg.Load = &load
+
+	client := newHTTPClient(cfg.RequestTimeout)
+	perHost := make([][]HarnessLoadLevel, len(cfg.Hosts))
+	forEachHost(cfg, func(i int, host HarnessHost) {
+		for _, model := range cfg.Models {
+			if cfg.Warmup {
+				fmt.Fprintf(cfg.Progress, "[%s] Warming up: %s\n", host.Name, model.Name)
+				_ = doWarmup(ctx, client, host.URL, model, cfg.Scenarios[0])
+			}
+			for _, n := range load.Concurrency {
+				if ctx.Err() != nil {
+					return
+				}
+				fmt.Fprintf(cfg.Progress, "[%s] Load: %s at concurrency %d\n", host.Name, model.Name, n)
+				perHost[i] = append(perHost[i], runLevel(ctx, client, cfg, host, model, n))
+			}
+		}
+	})
+
+	res := HarnessLoadResult{Config: cfg, GeneratedAt: time.Now()}
+	for _, levels := range perHost {
+		res.Levels = append(res.Levels, levels...)
+	}
+	return res, ctx.Err()
+}
+
+// validate checks the load settings once the suite, the overrides and the
+// defaults are merged.
+func (l HarnessLoadConfig) validate() error {
+	var errs []error
+	for i, c := range l.Concurrency {
+		if c <= 0 {
+			errs = append(errs, fmt.Errorf("load: concurrency[%d] must be positive", i))
+		}
+	}
+	if l.Duration <= 0 {
+		errs = append(errs, fmt.Errorf("load: duration %s must be positive", l.Duration))
+	}
+	if l.RampUp < 0 {
+		errs = append(errs, fmt.Errorf("load: ramp_up %s must not be negative", l.RampUp))
+	}
+	if l.Rate < 0 {
+		errs = append(errs, errors.New("load: rate must not be negative"))
+	}
+	return errors.Join(errs...)
+}
+
+// runLevel runs one concurrency level for the ramp-up and measured duration.
+// Requests still in flight at the end are waited for, but no new ones start.
+func runLevel(ctx context.Context, client *http.Client, cfg HarnessSuiteConfig, host HarnessHost, model HarnessModelConfig, concurrency int) HarnessLoadLevel {
+	load := cfg.Load
+	start := time.Now()
+	measureFrom := start.Add(load.RampUp)
+	end := measureFrom.Add(load.Duration)
+
+	var (
+		mu      sync.Mutex
+		samples []loadSample
+		next    atomic.Int64
+		wg      sync.WaitGroup
+	)
+	send := func() {
+		sc := cfg.Scenarios[int(next.Add(1)-1)%len(cfg.Scenarios)]
+		s := loadSample{start: time.Now()}
+		s.trial, s.err = GenerateAndMeasure(ctx, client, host.URL, model, sc, false)
+		s.end = time.Now()
+		mu.Lock()
+		samples = append(samples, s)
+		mu.Unlock()
+	}
+
+	skipped := 0
+	if load.Rate > 0 {
+		// Open loop: start requests at the rate, raised linearly over the
+		// ramp-up, and drop arrivals while every slot is busy.
+		slots := make(chan struct{}, concurrency)
+		for now := start; now.Before(end); now = time.Now() {
+			select {
+			case slots <- struct{}{}:
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					defer func() { <-slots }()
+					send()
+				}()
+			default:
+				if !now.Before(measureFrom) {
+					skipped++
+				}
+			}
+
+			rate := load.Rate
+			if now.Before(measureFrom) {
+				rate *= max(float64(now.Sub(start))/float64(load.RampUp), 0.1)
+			}
+			if !sleepCtx(ctx, time.Duration(float64(time.Second)/rate)) {
+				break
+			}
+		}
+	} else {
+		// Closed loop: workers start evenly over the ramp-up and each sends
+		// its next request when the previous one is done.
+		for w := 0; w < concurrency; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if !sleepCtx(ctx, load.RampUp*time.Duration(w)/time.Duration(concurrency)) {
+					return
+				}
+				for time.Now().Before(end) && ctx.Err() == nil {
+					send()
+				}
+			}()
+		}
+	}
+	wg.Wait()
+
+	level := summarizeLevel(samples, measureFrom)
+	level.Host = host.Name
+	level.ModelName = model.Name
+	level.Concurrency = concurrency
+	level.Skipped = skipped
+	return level
+}
+
+// summarizeLevel computes the statistics of the samples started at or after
+// measureFrom. Throughput is measured from measureFrom to the end of the
+// last measured request.
+func summarizeLevel(samples []loadSample, measureFrom time.Time) HarnessLoadLevel {
+	var (
+		level              HarnessLoadLevel
+		ttft, total, queue []float64
+		genTokens          int
+		last               = measureFrom
+	)
+	for _, s := range samples {
+		if s.start.Before(measureFrom) {
+			continue
+		}
+		if s.end.After(last) {
+			last = s.end
+		}
+		if s.err != nil {
+			level.Errors++
+			continue
+		}
+		level.Requests++
+		ttft = append(ttft, float64(s.trial.TTFTMillis))
+		total = append(total, float64(s.trial.TotalMillis))
+		queue = append(queue, float64(queueMillis(s.trial)))
+		genTokens += s.trial.GenEvalCount
+	}
+
+	level.WallSeconds = last.Sub(measureFrom).Seconds()
+	if level.WallSeconds > 0 {
+		level.RequestsPerSec = float64(level.Requests) / level.WallSeconds
+		level.GenTokensPerSec = float64(genTokens) / level.WallSeconds
+	}
+	level.TTFTP50, level.TTFTP95, level.TTFTP99 = p50(ttft), p95(ttft), p99(ttft)
+	level.TotalP50, level.TotalP95, level.TotalP99 = p50(total), p95(total), p99(total)
+	level.QueueP50, level.QueueP95 = p50(queue), p95(queue)
+	return level
+}
+
+// queueMillis is the server time of a request not spent evaluating the
+// prompt or generating: mostly waiting for the scheduler or a free slot when
+// more requests arrive than OLLAMA_NUM_PARALLEL allows, plus any model load.
+func queueMillis(t HarnessTrialResult) int64 {
+	return max(t.TotalServerMillis-t.PromptEvalMillis-t.GenEvalMillis, 0)
+}
+
+// parallelSlots estimates how many requests a host serves in parallel from
+// the levels of one model, in increasing concurrency: the highest level
+// before the median queue wait exceeds a quarter of the median latency. It
+// returns false when no level queues or even the first one does.
+func parallelSlots(levels []HarnessLoadLevel) (int, bool) {
+	for i, l := range levels {
+		if l.Requests > 0 && l.QueueP50 > l.TotalP50/4 {
+			if i == 0 {
+				return 0, false
+			}
+			return levels[i-1].Concurrency, true
+		}
+	}
+	return 0, false
+}
+
+// sleepCtx sleeps for d and reports whether ctx is still active.
+func sleepCtx(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
@@ -0,0 +1,119 @@
+// harness/load_test.go
+package harness
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// queueingOllama serves one request at a time, each taking work, and
+// reports the time spent waiting in total_duration like Ollama does.
+func queueingOllama(t *testing.T, work time.Duration) *httptest.Server {
+	t.Helper()
+	slot := make(chan struct{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		arrived := time.Now()
+		slot <- struct{}{}
+		defer func() { <-slot }()
+		time.Sleep(work)
+		fmt.Fprintln(w, `{"response": "hi", "done": false}`)
+		fmt.Fprintf(w, `{"response": "", "done": true, "eval_count": 10, "eval_duration": %d, "total_duration": %d}`+"\n", work, time.Since(arrived))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func loadConfig(url string, load HarnessLoadConfig) HarnessSuiteConfig {
+	return HarnessSuiteConfig{
+		Hosts:     []HarnessHost{{Name: "local", URL: url}},
+		Models:    []HarnessModelConfig{{Name: "m"}},
+		Scenarios: []HarnessPromptScenario{{ID: "a", Prompt: "hello"}, {ID: "b", Prompt: "bye"}},
+		Load:      &load,
+	}
+}
+
+func TestRunLoadSuiteClosedLoop(t *testing.T) {
+	server := queueingOllama(t, 20*time.Millisecond)
+	cfg := loadConfig(server.URL, HarnessLoadConfig{Concurrency: []int{1, 3}, Duration: 200 * time.Millisecond, RampUp: 20 * time.Millisecond})
+
+	res, err := RunLoadSuite(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("RunLoadSuite: %v", err)
+	}
+	if len(res.Levels) != 2 {
+		t.Fatalf("expected one level per concurrency, got %+v", res.Levels)
+	}
+	one, three := res.Levels[0], res.Levels[1]
+	if one.Requests == 0 || three.Requests == 0 || one.Errors+three.Errors != 0 {
+		t.Fatalf("expected successful requests at both levels, got %+v", res.Levels)
+	}
+	// One slot caps throughput, so extra workers only wait.
+	if three.QueueP50 < 20 || one.QueueP50 > 10 {
+		t.Errorf("expected queue wait only at concurrency 3, got %v and %v ms", one.QueueP50, three.QueueP50)
+	}
+	if three.RequestsPerSec > one.RequestsPerSec*1.5 {
+		t.Errorf("expected no throughput gain beyond one slot, got %.1f and %.1f req/s", one.RequestsPerSec, three.RequestsPerSec)
+	}
+	if n, ok := parallelSlots(res.Levels); !ok || n != 1 {
+		t.Errorf("expected one parallel slot, got %d, %v", n, ok)
+	}
+}
+
+func TestRunLoadSuiteFixedRate(t *testing.T) {
+	server := queueingOllama(t, time.Millisecond)
+	cfg := loadConfig(server.URL, HarnessLoadConfig{Concurrency: []int{2}, Duration: 300 * time.Millisecond, Rate: 20})
+
+	res, err := RunLoadSuite(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("RunLoadSuite: %v", err)
+	}
+	// 20 req/s for 0.3s starts about 6 requests.
+	if got := res.Levels[0].Requests; got < 3 || got > 8 {
+		t.Errorf("expected about 6 requests at 20 req/s, got %d", got)
+	}
+}
+
+func TestRunLoadRejectsInvalidOverrides(t *testing.T) {
+	suite := gateSuite(t, t.TempDir(), "load", "http://localhost:1", "")
+	for _, tc := range []struct {
+		opts LoadOptions
+		want string
+	}{
+		{LoadOptions{Concurrency: []int{-1}, Rate: 1}, "load: concurrency[0] must be positive"},
+		{LoadOptions{Concurrency: []int{1, 0}}, "load: concurrency[1] must be positive"},
+		{LoadOptions{Duration: -time.Second}, "load: duration -1s must be positive"},
+		{LoadOptions{RampUp: -time.Second}, "load: ramp_up -1s must not be negative"},
+		{LoadOptions{Rate: -2}, "load: rate must not be negative"},
+	} {
+		tc.opts.SuitePath = suite
+		err := RunLoad(context.Background(), tc.opts, io.Discard, io.Discard)
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%+v: expected %q, got %v", tc.opts, tc.want, err)
+		}
+	}
+}
+
+func TestParallelSlots(t *testing.T) {
+	level := func(c int, queue float64) HarnessLoadLevel {
+		return HarnessLoadLevel{Concurrency: c, Requests: 1, TotalP50: 100, QueueP50: queue}
+	}
+	for _, tc := range []struct {
+		levels []HarnessLoadLevel
+		want   int
+		ok     bool
+	}{
+		{[]HarnessLoadLevel{level(1, 0), level(2, 5), level(4, 60)}, 2, true},
+		{[]HarnessLoadLevel{level(1, 0), level(2, 0)}, 0, false},
+		{[]HarnessLoadLevel{level(1, 80)}, 0, false},
+	} {
+		if n, ok := parallelSlots(tc.levels); n != tc.want || ok != tc.ok {
+			t.Errorf("parallelSlots(%+v) = %d, %v; want %d, %v", tc.levels, n, ok, tc.want, tc.ok)
+		}
+	}
+}
@@ -47,3 +47,12 @@ func meanStd(values []float64) (mean, std float64) {
 	std = math.Sqrt(varsum / n)
 	return
 }
+
+// cv returns the coefficient of variation, std / mean, or 0 for a zero mean.
+func cv(values []float64) float64 {
+	mean, std := meanStd(values)
+	if mean == 0 {
+		return 0
+	}
+	return std / mean
+}
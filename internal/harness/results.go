@@ -2,47 +2,116 @@
 // Package: harness
 package harness
 
-import "time"
+import (
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
 
-// summarize builds per-model summaries from TrialResult rows (warm runs only unless cold-only).
+// failed reports whether the trial is a synthetic row for a failed request.
+func (t HarnessTrialResult) failed() bool {
+	return strings.HasPrefix(t.DoneReason, "error")
+}
+
+// summaryKey identifies the trials that are summarized together.
+type summaryKey struct {
+	host  string
+	model string
+}
+
+// summarize builds per-host, per-model summaries from TrialResult rows. Warm
+// and cold trials are summarized apart, and warm trials also per scenario.
 func summarize(trials []HarnessTrialResult) []HarnessModelSummary {
-	byModel := map[string][]HarnessTrialResult{}
+	byModel := map[summaryKey][]HarnessTrialResult{}
 	for _, t := range trials {
-		byModel[t.ModelName] = append(byModel[t.ModelName], t)
+		key := summaryKey{host: t.Host, model: t.ModelName}
+		byModel[key] = append(byModel[key], t)
 	}
 
 	out := make([]HarnessModelSummary, 0, len(byModel))
-	for m, rows := range byModel {
-		var ttftVals []float64
-		var totalVals []float64
-		var genTPS []float64
-
+	for key, rows := range byModel {
+		var warm, cold []HarnessTrialResult
+		var scenarios []string
+		byScenario := map[string][]HarnessTrialResult{}
 		for _, r := range rows {
-			// Typically you want warm runs; but we include all trials here.
-			ttftVals = append(ttftVals, float64(r.TTFTMillis))
-			totalVals = append(totalVals, float64(r.TotalMillis))
-			if r.GenTokensPerSec > 0 {
-				genTPS = append(genTPS, r.GenTokensPerSec)
+			if r.Cold {
+				cold = append(cold, r)
+				continue
 			}
+			warm = append(warm, r)
+			if _, ok := byScenario[r.ScenarioID]; !ok {
+				scenarios = append(scenarios, r.ScenarioID)
+			}
+			byScenario[r.ScenarioID] = append(byScenario[r.ScenarioID], r)
 		}
 
 		ms := HarnessModelSummary{
-			ModelName:  m,
-			TTFTP50:    simpleQuantile(ttftVals, 0.50),
-			TTFTP95:    simpleQuantile(ttftVals, 0.95),
-			TotalP50:   simpleQuantile(totalVals, 0.50),
-			TotalP95:   simpleQuantile(totalVals, 0.95),
-			GenTPSMean: 0,
-			GenTPSStd:  0,
+			Host:         key.host,
+			ModelName:    key.model,
+			HarnessStats: computeStats(warm),
+		}
+		if len(cold) > 0 {
+			stats := computeStats(cold)
+			ms.Cold = &stats
 		}
-		if len(genTPS) > 0 {
-			ms.GenTPSMean, ms.GenTPSStd = meanStd(genTPS)
+		for _, id := range scenarios {
+			ms.Scenarios = append(ms.Scenarios, HarnessScenarioSummary{ScenarioID: id, HarnessStats: computeStats(byScenario[id])})
 		}
 		out = append(out, ms)
 	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Host != out[j].Host {
+			return out[i].Host < out[j].Host
+		}
+		return out[i].ModelName < out[j].ModelName
+	})
 	return out
 }
 
+// computeStats computes the statistics of rows; see HarnessStats.
+func computeStats(rows []HarnessTrialResult) HarnessStats {
+	st := HarnessStats{Trials: len(rows)}
+	var ok []HarnessTrialResult
+	for _, r := range rows {
+		if r.failed() {
+			st.Errors++
+			continue
+		}
+		ok = append(ok, r)
+	}
+	if st.Trials > 0 {
+		st.ErrorRate = float64(st.Errors) / float64(st.Trials)
+	}
+	if len(ok) == 0 {
+		return st
+	}
+
+	ttft := samples(ok, func(t HarnessTrialResult) float64 { return float64(t.TTFTMillis) })
+	total := samples(ok, func(t HarnessTrialResult) float64 { return float64(t.TotalMillis) })
+	load := samples(ok, func(t HarnessTrialResult) float64 { return float64(t.LoadMillis) })
+	st.TTFTP50, st.TTFTP95, st.TTFTP99 = p50(ttft), p95(ttft), p99(ttft)
+	st.TTFTMin, st.TTFTMax, st.TTFTCV = slices.Min(ttft), slices.Max(ttft), cv(ttft)
+	st.TotalP50, st.TotalP95, st.TotalP99 = p50(total), p95(total), p99(total)
+	st.TotalMin, st.TotalMax, st.TotalCV = slices.Min(total), slices.Max(total), cv(total)
+	st.LoadMean, st.LoadP50, st.LoadP95, st.LoadMax = mean(load), p50(load), p95(load), slices.Max(load)
+
+	// Trials without token counts report no rate.
+	var genTPS, promptTPS []float64
+	for _, r := range ok {
+		if r.GenTokensPerSec > 0 {
+			genTPS = append(genTPS, r.GenTokensPerSec)
+		}
+		if r.PromptTokensPerSec > 0 {
+			promptTPS = append(promptTPS, r.PromptTokensPerSec)
+		}
+	}
+	st.GenTPSMean, st.GenTPSStd = meanStd(genTPS)
+	st.GenTPSCV = cv(genTPS)
+	st.PromptTPSMean, st.PromptTPSStd = meanStd(promptTPS)
+	return st
+}
+
 // buildHarnessSuiteResult packs everything with a timestamp.
 func buildHarnessSuiteResult(cfg HarnessSuiteConfig, trials []HarnessTrialResult) HarnessSuiteResult {
 	return HarnessSuiteResult{
@@ -0,0 +1,60 @@
+// harness/results_test.go
+package harness
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestSummarizeSeparatesColdWarmAndScenarios(t *testing.T) {
+	model := HarnessModelConfig{Name: "m"}
+	trial := func(scenario string, cold bool, ttft int64, tps float64) HarnessTrialResult {
+		return HarnessTrialResult{Host: "h", ModelName: "m", ScenarioID: scenario, Cold: cold, TTFTMillis: ttft, TotalMillis: ttft * 2, GenTokensPerSec: tps, PromptTokensPerSec: 2 * tps}
+	}
+	cold := trial("short", true, 3000, 10)
+	cold.LoadMillis = 2500
+	failed := failedTrial(model, HarnessPromptScenario{ID: "long"}, false, errors.New("boom"))
+	failed.Host = "h"
+	trials := []HarnessTrialResult{
+		cold,
+		trial("short", false, 100, 20), trial("short", false, 200, 20), trial("short", false, 300, 20),
+		trial("long", false, 400, 10), failed,
+	}
+
+	reports := summarize(trials)
+	if len(reports) != 1 {
+		t.Fatalf("expected one summary, got %+v", reports)
+	}
+	m := reports[0]
+	if m.Trials != 5 || m.Errors != 1 || m.ErrorRate != 0.2 {
+		t.Errorf("expected 5 warm trials with one error, got %d, %d, %v", m.Trials, m.Errors, m.ErrorRate)
+	}
+	// The failed row's zero latency must not reach the statistics.
+	if m.TTFTMin != 100 || m.TTFTMax != 400 || m.TotalMax != 800 || m.TTFTP99 < m.TTFTP95 {
+		t.Errorf("unexpected warm latency stats: %+v", m.HarnessStats)
+	}
+	if m.GenTPSMean != 17.5 || m.PromptTPSMean != 35 {
+		t.Errorf("unexpected warm throughput: gen %v, prompt %v", m.GenTPSMean, m.PromptTPSMean)
+	}
+	if want := math.Sqrt(12500) / 250; math.Abs(m.TTFTCV-want) > 1e-9 {
+		t.Errorf("expected TTFT CV %v, got %v", want, m.TTFTCV)
+	}
+	if m.Cold == nil || m.Cold.Trials != 1 || m.Cold.TTFTP50 != 3000 || m.Cold.LoadMax != 2500 {
+		t.Errorf("expected the cold trial apart, got %+v", m.Cold)
+	}
+	if len(m.Scenarios) != 2 || m.Scenarios[0].ScenarioID != "short" || m.Scenarios[0].Trials != 3 ||
+		m.Scenarios[1].ScenarioID != "long" || m.Scenarios[1].Errors != 1 || m.Scenarios[1].ErrorRate != 0.5 {
+		t.Errorf("unexpected scenario breakdown: %+v", m.Scenarios)
+	}
+
+	var out bytes.Buffer
+	writeSummary(&out, reports)
+	for _, want := range []string{"Warm: 5 trials, 1 errors (20.0%)", "Cold: 1 trials", "Load p50/p95/max: 2500.0 / 2500.0 / 2500.0 ms", "SCENARIO", "long"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected %q in summary, got:\n%s", want, out.String())
+		}
+	}
+}
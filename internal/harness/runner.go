@@ -6,21 +6,51 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"sync"
 	"time"
 )
 
 // RunSpeedSuite is the single exported entrypoint.
 // Provide a fully-populated SuiteConfig, and it returns detailed results.
+// The suite runs once per host; up to HostParallelism hosts run at a time.
 func RunSpeedSuite(ctx context.Context, cfg HarnessSuiteConfig) (HarnessSuiteResult, error) {
-	if cfg.BaseURL == "" {
-		return HarnessSuiteResult{}, errors.New("BaseURL is required (e.g., http://localhost:11434)")
+	if err := prepareSuite(&cfg); err != nil {
+		return HarnessSuiteResult{}, err
+	}
+
+	client := newHTTPClient(cfg.RequestTimeout)
+	perHost := make([][]HarnessTrialResult, len(cfg.Hosts))
+	forEachHost(cfg, func(i int, host HarnessHost) {
+		perHost[i] = runHost(ctx, client, cfg, host)
+	})
+
+	var all []HarnessTrialResult
+	for _, trials := range perHost {
+		all = append(all, trials...)
+	}
+	return buildHarnessSuiteResult(cfg, all), nil
+}
+
+// prepareSuite validates cfg and fills in defaults.
+func prepareSuite(cfg *HarnessSuiteConfig) error {
+	if len(cfg.Hosts) == 0 {
+		return errors.New("at least one host is required (e.g., http://localhost:11434)")
+	}
+	for i, h := range cfg.Hosts {
+		if h.URL == "" {
+			return fmt.Errorf("host %d: URL is required", i+1)
+		}
+		if h.Name == "" {
+			cfg.Hosts[i].Name = h.URL
+		}
 	}
 	if len(cfg.Models) == 0 {
-		return HarnessSuiteResult{}, errors.New("at least one ModelConfig is required")
+		return errors.New("at least one ModelConfig is required")
 	}
 	if len(cfg.Scenarios) == 0 {
-		return HarnessSuiteResult{}, errors.New("at least one PromptScenario is required")
+		return errors.New("at least one PromptScenario is required")
 	}
 	if cfg.Trials <= 0 {
 		cfg.Trials = 5
@@ -28,50 +58,78 @@ func RunSpeedSuite(ctx context.Context, cfg HarnessSuiteConfig) (HarnessSuiteRes
 	if cfg.RequestTimeout <= 0 {
 		cfg.RequestTimeout = 60 * time.Second
 	}
+	if cfg.HostParallelism <= 0 {
+		cfg.HostParallelism = 1
+	}
+	if cfg.Progress == nil {
+		cfg.Progress = io.Discard
+	}
+	return nil
+}
 
-	client := newHTTPClient(cfg.RequestTimeout)
+// forEachHost calls run for every host, with up to cfg.HostParallelism
+// hosts at a time, and waits for all of them.
+func forEachHost(cfg HarnessSuiteConfig, run func(i int, host HarnessHost)) {
+	sem := make(chan struct{}, cfg.HostParallelism)
+	var wg sync.WaitGroup
+	for i, host := range cfg.Hosts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			run(i, host)
+		}()
+	}
+	wg.Wait()
+}
+
+// runHost runs every model and scenario of the suite against one host.
+func runHost(ctx context.Context, client *http.Client, cfg HarnessSuiteConfig, host HarnessHost) []HarnessTrialResult {
 	var all []HarnessTrialResult
+	record := func(tr HarnessTrialResult) {
+		tr.Host = host.Name
+		all = append(all, tr)
+	}
 
 	for _, model := range cfg.Models {
-		// Optional warm-up (not recorded)
-		fmt.Println("Warming up:", model)
-		if cfg.Warmup {
-			_ = doWarmup(ctx, client, cfg.BaseURL, model, cfg.Scenarios[0])
+		// Cold starts come first, while the warm-up has not loaded the model.
+		for i := 0; i < cfg.ColdTrials; i++ {
+			fmt.Fprintf(cfg.Progress, "[%s] Cold start: %s (%d/%d)\n", host.Name, model.Name, i+1, cfg.ColdTrials)
+			record(coldTrial(ctx, client, host.URL, model, cfg.Scenarios[0]))
 		}
 
-		// Optional single cold trial (tagged Cold=true)
-		if cfg.IncludeCold {
-			tr, err := GenerateAndMeasure(ctx, client, cfg.BaseURL, model, cfg.Scenarios[0], true)
-			if err == nil {
-				all = append(all, tr)
-			}
-			// Deliberately ignore cold errors to avoid aborting the whole suite.
+		// Optional warm-up (not recorded)
+		fmt.Fprintf(cfg.Progress, "[%s] Warming up: %s\n", host.Name, model.Name)
+		if cfg.Warmup {
+			_ = doWarmup(ctx, client, host.URL, model, cfg.Scenarios[0])
 		}
 
 		// Warm trials across all scenarios
 		for _, sc := range cfg.Scenarios {
 			for i := 0; i < cfg.Trials; i++ {
-				fmt.Println("GenerateAndMeasure:", sc)
-				tr, err := GenerateAndMeasure(ctx, client, cfg.BaseURL, model, sc, false)
+				fmt.Fprintf(cfg.Progress, "[%s] GenerateAndMeasure: %s / %s (%d/%d)\n", host.Name, model.Name, sc.ID, i+1, cfg.Trials)
+				tr, err := GenerateAndMeasure(ctx, client, host.URL, model, sc, false)
 				if err != nil {
-					// Record a synthetic failed row to make issues visible without aborting.
-					all = append(all, HarnessTrialResult{
-						ModelName:      model.Name,
-						ScenarioID:     sc.ID,
-						Cold:           false,
-						PromptLenChars: len(sc.Prompt),
-						TTFTMillis:     0,
-						TotalMillis:    0,
-						DoneReason:     fmt.Sprintf("error: %v", err),
-					})
-					continue
+					tr = failedTrial(model, sc, false, err)
 				}
-				all = append(all, tr)
+				record(tr)
 			}
 		}
 	}
+	return all
+}
 
-	return buildHarnessSuiteResult(cfg, all), nil
+// failedTrial is a synthetic failed row that makes issues visible without
+// aborting the suite; see HarnessTrialResult.failed.
+func failedTrial(model HarnessModelConfig, sc HarnessPromptScenario, cold bool, err error) HarnessTrialResult {
+	return HarnessTrialResult{
+		ModelName:      model.Name,
+		ScenarioID:     sc.ID,
+		Cold:           cold,
+		PromptLenChars: len(sc.Prompt),
+		DoneReason:     fmt.Sprintf("error: %v", err),
+	}
 }
 
 func doWarmup(ctx context.Context, c *http.Client, base string, model HarnessModelConfig, scenario HarnessPromptScenario) error {
@@ -0,0 +1,50 @@
+// harness/runner_test.go
+package harness
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// fakeOllama streams a fixed /api/generate answer, reporting eval counts
+// that yield gen TPS, so hosts can be told apart in results.
+func fakeOllama(t *testing.T, tps int) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"response": "hi", "done": false}`)
+		fmt.Fprintf(w, `{"response": "", "done": true, "done_reason": "stop", "eval_count": %d, "eval_duration": 1000000000}`+"\n", tps)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestRunSpeedSuiteAcrossHosts(t *testing.T) {
+	fast, slow := fakeOllama(t, 40), fakeOllama(t, 10)
+	cfg := HarnessSuiteConfig{
+		Hosts:           []HarnessHost{{Name: "fast", URL: fast.URL}, {Name: "slow", URL: slow.URL}},
+		HostParallelism: 2,
+		Models:          []HarnessModelConfig{{Name: "m"}},
+		Scenarios:       []HarnessPromptScenario{{ID: "short", Prompt: "hello"}},
+		Trials:          2,
+	}
+
+	res, err := RunSpeedSuite(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("RunSpeedSuite: %v", err)
+	}
+	if len(res.Trials) != 4 || res.Trials[0].Host != "fast" || res.Trials[3].Host != "slow" {
+		t.Fatalf("expected two trials per host in host order, got %+v", res.Trials)
+	}
+	if len(res.ModelReports) != 2 {
+		t.Fatalf("expected one summary per host and model, got %+v", res.ModelReports)
+	}
+	for _, m := range res.ModelReports {
+		want := map[string]float64{"fast": 40, "slow": 10}[m.Host]
+		if m.GenTPSMean != want {
+			t.Errorf("host %s: expected gen TPS %v, got %v", m.Host, want, m.GenTPSMean)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+// harness/store.go
+// Package: harness
+package harness
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// runTimeFormat is the timestamp at the start of result file names, so the
+// files sort by the time of the run.
+const runTimeFormat = "20060102-150405"
+
+// SaveResult assigns the result a run id and writes it to dir as
+// <timestamp>-<run id>.json. The run id is the first seven hex digits of the
+// SHA-1 of the result, like a short git commit id. It returns the file path.
+func SaveResult(dir string, res *HarnessSuiteResult) (string, error) {
+	res.RunID = ""
+	b, err := json.Marshal(res)
+	if err != nil {
+		return "", fmt.Errorf("could not encode result: %w", err)
+	}
+	sum := sha1.Sum(b)
+	res.RunID = hex.EncodeToString(sum[:])[:7]
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("could not create results directory: %w", err)
+	}
+	path := filepath.Join(dir, res.GeneratedAt.Format(runTimeFormat)+"-"+res.RunID+".json")
+	b, _ = json.MarshalIndent(res, "", "  ")
+	if err := os.WriteFile(path, append(b, '\n'), 0o644); err != nil {
+		return "", fmt.Errorf("could not write result: %w", err)
+	}
+	return path, nil
+}
+
+// SaveLoadResult writes a load test result to the load subdirectory of dir,
+// kept apart from the runs LoadResult finds, as <timestamp>.json. It returns
+// the file path.
+func SaveLoadResult(dir string, res HarnessLoadResult) (string, error) {
+	dir = filepath.Join(dir, "load")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("could not create results directory: %w", err)
+	}
+	path := filepath.Join(dir, res.GeneratedAt.Format(runTimeFormat)+".json")
+	b, err := json.MarshalIndent(res, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("could not encode result: %w", err)
+	}
+	if err := os.WriteFile(path, append(b, '\n'), 0o644); err != nil {
+		return "", fmt.Errorf("could not write result: %w", err)
+	}
+	return path, nil
+}
+
+// LoadResult reads a saved result. ref is the path of a result file, a run id
+// or unique prefix of one in dir, or "latest" for the most recent run in dir.
+func LoadResult(dir, ref string) (HarnessSuiteResult, error) {
+	path := ref
+	if _, err := os.Stat(ref); err != nil {
+		if path, err = findRun(dir, ref); err != nil {
+			return HarnessSuiteResult{}, err
+		}
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return HarnessSuiteResult{}, fmt.Errorf("could not read result: %w", err)
+	}
+	var res HarnessSuiteResult
+	if err := json.Unmarshal(b, &res); err != nil {
+		return HarnessSuiteResult{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return res, nil
+}
+
+// findRun returns the path of the result in dir that ref names.
+func findRun(dir, ref string) (string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil || len(files) == 0 {
+		return "", fmt.Errorf("no saved runs in %s", dir)
+	}
+	sort.Strings(files)
+	if ref == "latest" {
+		return files[len(files)-1], nil
+	}
+
+	var matches []string
+	for _, f := range files {
+		name := strings.TrimSuffix(filepath.Base(f), ".json")
+		if id := name[strings.LastIndex(name, "-")+1:]; strings.HasPrefix(id, ref) {
+			matches = append(matches, f)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("run %q not found in %s", ref, dir)
+	case 1:
+		return matches[0], nil
+	}
+	return "", fmt.Errorf("run %q is ambiguous in %s", ref, dir)
+}
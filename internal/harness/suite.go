@@ -0,0 +1,292 @@
+// harness/suite.go
+// Package: harness
+package harness
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// suiteFile is the on-disk form of a HarnessSuiteConfig. YAML suites are
+// converted to JSON first, so the json tags apply to both formats.
+type suiteFile struct {
+	BaseURL   string               `json:"base_url"`         // shorthand for a single host
+	Hosts     []HarnessHost        `json:"hosts"`            // defaults to the hosts of the main config
+	Parallel  int                  `json:"host_parallelism"` // hosts benchmarked at once
+	Options   map[string]any       `json:"options"`          // defaults for every model; model options take precedence
+	Models    []HarnessModelConfig `json:"models"`
+	Scenarios []suiteScenario      `json:"scenarios"`
+	Trials    int                  `json:"trials"`
+	Warmup    *bool                `json:"warmup"`  // defaults to true
+	Cold      coldTrials           `json:"cold"`    // see HarnessSuiteConfig.ColdTrials
+	Timeout   string               `json:"timeout"` // Go duration, e.g. "2m"
+
+	Thresholds []HarnessThreshold `json:"thresholds"`
+	Baseline   *HarnessBaseline   `json:"baseline"`
+	Load       *suiteLoad         `json:"load"`
+}
+
+// suiteLoad is the load section of a suite file; see HarnessLoadConfig.
+type suiteLoad struct {
+	Concurrency []int   `json:"concurrency"`
+	Duration    string  `json:"duration"` // Go duration, e.g. "30s"
+	RampUp      string  `json:"ramp_up"`
+	Rate        float64 `json:"rate"`
+}
+
+// coldTrials is the number of cold starts in a suite file. It also accepts
+// a boolean, where true means one.
+type coldTrials int
+
+func (c *coldTrials) UnmarshalJSON(b []byte) error {
+	var on bool
+	if err := json.Unmarshal(b, &on); err == nil {
+		*c = 0
+		if on {
+			*c = 1
+		}
+		return nil
+	}
+	var n int
+	if err := json.Unmarshal(b, &n); err != nil {
+		return errors.New("cold must be a number of trials or a boolean")
+	}
+	*c = coldTrials(n)
+	return nil
+}
+
+// suiteScenario is a scenario in a suite file. Exactly one of Prompt,
+// PromptFile and FillerChars sets the prompt.
+type suiteScenario struct {
+	ID          string `json:"id"`
+	Description string `json:"description"`
+	Prompt      string `json:"prompt"`
+	PromptFile  string `json:"prompt_file"`  // relative to the suite file
+	FillerChars int    `json:"filler_chars"` // see MakeFillerPrompt
+}
+
+// LoadSuite reads a suite definition from a .yaml, .yml or .json file and
+// returns the validated HarnessSuiteConfig. Every problem found is reported
+// in the returned error. A suite without hosts leaves Hosts empty.
+func LoadSuite(path string) (HarnessSuiteConfig, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return HarnessSuiteConfig{}, fmt.Errorf("could not read suite file: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		var doc any
+		if err := yaml.Unmarshal(b, &doc); err != nil {
+			return HarnessSuiteConfig{}, fmt.Errorf("%s: %w", path, err)
+		}
+		if b, err = json.Marshal(doc); err != nil {
+			return HarnessSuiteConfig{}, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	var file suiteFile
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&file); err != nil {
+		return HarnessSuiteConfig{}, fmt.Errorf("%s: %w", path, err)
+	}
+
+	cfg, err := file.build(filepath.Dir(path))
+	if err != nil {
+		return HarnessSuiteConfig{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// build validates the suite and resolves prompts, defaults and options.
+// Relative prompt files are read from dir.
+func (f suiteFile) build(dir string) (HarnessSuiteConfig, error) {
+	var errs []error
+	cfg := HarnessSuiteConfig{
+		HostParallelism: f.Parallel,
+		Trials:          f.Trials,
+		Warmup:          f.Warmup == nil || *f.Warmup,
+		ColdTrials:      int(f.Cold),
+	}
+
+	hosts := f.Hosts
+	if f.BaseURL != "" {
+		if len(hosts) > 0 {
+			errs = append(errs, errors.New("set either base_url or hosts, not both"))
+		}
+		hosts = []HarnessHost{{URL: f.BaseURL}}
+	}
+	for i, h := range hosts {
+		h.URL = strings.TrimSuffix(h.URL, "/")
+		if h.URL == "" {
+			errs = append(errs, fmt.Errorf("hosts[%d]: url is required", i))
+		}
+		if h.Name == "" {
+			h.Name = h.URL
+		}
+		cfg.Hosts = append(cfg.Hosts, h)
+	}
+	if f.Parallel < 0 {
+		errs = append(errs, errors.New("host_parallelism must not be negative"))
+	}
+	if f.Trials < 0 {
+		errs = append(errs, errors.New("trials must not be negative"))
+	}
+	if f.Cold < 0 {
+		errs = append(errs, errors.New("cold must not be negative"))
+	}
+	if f.Timeout != "" {
+		d, err := time.ParseDuration(f.Timeout)
+		if err != nil || d <= 0 {
+			errs = append(errs, fmt.Errorf("timeout %q is not a positive duration", f.Timeout))
+		}
+		cfg.RequestTimeout = d
+	}
+
+	if len(f.Models) == 0 {
+		errs = append(errs, errors.New("at least one model is required"))
+	}
+	for i, m := range f.Models {
+		if m.Name == "" {
+			errs = append(errs, fmt.Errorf("models[%d]: name is required", i))
+		}
+		options := maps.Clone(f.Options)
+		if options == nil {
+			options = map[string]any{}
+		}
+		maps.Copy(options, m.Options)
+		if len(options) == 0 {
+			options = nil
+		}
+		m.Options = options
+		cfg.Models = append(cfg.Models, m)
+	}
+
+	if len(f.Scenarios) == 0 {
+		errs = append(errs, errors.New("at least one scenario is required"))
+	}
+	seen := map[string]bool{}
+	for i, s := range f.Scenarios {
+		if s.ID == "" {
+			errs = append(errs, fmt.Errorf("scenarios[%d]: id is required", i))
+		} else if seen[s.ID] {
+			errs = append(errs, fmt.Errorf("scenarios[%d]: duplicate id %q", i, s.ID))
+		}
+		seen[s.ID] = true
+
+		prompt, err := s.prompt(dir)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("scenarios[%d]: %w", i, err))
+		}
+		cfg.Scenarios = append(cfg.Scenarios, HarnessPromptScenario{ID: s.ID, Description: s.Description, Prompt: prompt})
+	}
+
+	for i, th := range f.Thresholds {
+		if th.MaxTTFTP95 == 0 && th.MaxTotalP95 == 0 && th.MinGenTPS == 0 && th.MaxErrorRate == nil {
+			errs = append(errs, fmt.Errorf("thresholds[%d]: set at least one limit", i))
+		}
+		if th.MaxTTFTP95 < 0 || th.MaxTotalP95 < 0 || th.MinGenTPS < 0 {
+			errs = append(errs, fmt.Errorf("thresholds[%d]: limits must not be negative", i))
+		}
+		if r := th.MaxErrorRate; r != nil && (*r < 0 || *r > 1) {
+			errs = append(errs, fmt.Errorf("thresholds[%d]: max_error_rate must be between 0 and 1", i))
+		}
+	}
+	cfg.Thresholds = f.Thresholds
+	if b := f.Baseline; b != nil {
+		if b.Run == "" {
+			errs = append(errs, errors.New("baseline: run is required"))
+		}
+		if b.MaxRegressionPct <= 0 {
+			errs = append(errs, errors.New("baseline: max_regression_pct must be positive"))
+		}
+		cfg.Baseline = b
+	}
+
+	if f.Load != nil {
+		load, err := f.Load.build()
+		if err != nil {
+			errs = append(errs, err)
+		}
+		cfg.Load = &load
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return HarnessSuiteConfig{}, err
+	}
+	return cfg, nil
+}
+
+// build validates the load section. Unset values are left for
+// RunLoadSuite to default.
+func (l suiteLoad) build() (HarnessLoadConfig, error) {
+	var errs []error
+	load := HarnessLoadConfig{Concurrency: l.Concurrency, Rate: l.Rate}
+	for i, c := range l.Concurrency {
+		if c <= 0 {
+			errs = append(errs, fmt.Errorf("load: concurrency[%d] must be positive", i))
+		}
+	}
+	if l.Duration != "" {
+		d, err := time.ParseDuration(l.Duration)
+		if err != nil || d <= 0 {
+			errs = append(errs, fmt.Errorf("load: duration %q is not a positive duration", l.Duration))
+		}
+		load.Duration = d
+	}
+	if l.RampUp != "" {
+		d, err := time.ParseDuration(l.RampUp)
+		if err != nil || d < 0 {
+			errs = append(errs, fmt.Errorf("load: ramp_up %q is not a duration", l.RampUp))
+		}
+		load.RampUp = d
+	}
+	if l.Rate < 0 {
+		errs = append(errs, errors.New("load: rate must not be negative"))
+	}
+	return load, errors.Join(errs...)
+}
+
+// prompt resolves the scenario's prompt text.
+func (s suiteScenario) prompt(dir string) (string, error) {
+	set := 0
+	for _, ok := range []bool{s.Prompt != "", s.PromptFile != "", s.FillerChars != 0} {
+		if ok {
+			set++
+		}
+	}
+	if set != 1 {
+		return "", errors.New("set exactly one of prompt, prompt_file and filler_chars")
+	}
+
+	switch {
+	case s.PromptFile != "":
+		path := s.PromptFile
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("could not read prompt file: %w", err)
+		}
+		if strings.TrimSpace(string(b)) == "" {
+			return "", fmt.Errorf("prompt file %s is empty", s.PromptFile)
+		}
+		return string(b), nil
+	case s.FillerChars < 0:
+		return "", errors.New("filler_chars must be positive")
+	case s.FillerChars > 0:
+		return MakeFillerPrompt(s.FillerChars), nil
+	}
+	return s.Prompt, nil
+}
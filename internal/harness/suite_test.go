@@ -0,0 +1,121 @@
+// harness/suite_test.go
+package harness
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadSuiteYAML(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "prompts/review.txt", "Review this code.")
+	path := writeFile(t, dir, "suite.yaml", `
+base_url: http://localhost:11434/
+options:
+  temperature: 0.0
+  num_predict: 256
+models:
+  - name: a
+  - name: b
+    options:
+      num_predict: 64
+scenarios:
+  - id: short
+    filler_chars: 128
+  - id: review
+    prompt_file: prompts/review.txt
+trials: 2
+cold: true
+timeout: 90s
+load:
+  concurrency: [1, 4]
+  duration: 1m
+  ramp_up: 10s
+`)
+
+	cfg, err := LoadSuite(path)
+	if err != nil {
+		t.Fatalf("LoadSuite: %v", err)
+	}
+	if len(cfg.Hosts) != 1 || cfg.Hosts[0].URL != "http://localhost:11434" || cfg.Hosts[0].Name != "http://localhost:11434" || cfg.Trials != 2 || !cfg.Warmup || cfg.ColdTrials != 1 || cfg.RequestTimeout != 90*time.Second {
+		t.Fatalf("unexpected suite settings: %+v", cfg)
+	}
+	if cfg.Models[0].Options["num_predict"] != 256.0 || cfg.Models[1].Options["num_predict"] != 64.0 || cfg.Models[1].Options["temperature"] != 0.0 {
+		t.Fatalf("expected suite options merged under model options, got %+v", cfg.Models)
+	}
+	if l := cfg.Load; l == nil || len(l.Concurrency) != 2 || l.Duration != time.Minute || l.RampUp != 10*time.Second || l.Rate != 0 {
+		t.Fatalf("unexpected load settings: %+v", cfg.Load)
+	}
+	if len(cfg.Scenarios[0].Prompt) != 128 || cfg.Scenarios[1].Prompt != "Review this code." {
+		t.Fatalf("unexpected scenario prompts: %+v", cfg.Scenarios)
+	}
+}
+
+func TestLoadSuiteValidation(t *testing.T) {
+	dir := t.TempDir()
+	path := writeFile(t, dir, "suite.json", `{
+  "base_url": "http://a",
+  "hosts": [{"name": "b"}],
+  "models": [{"name": ""}],
+  "scenarios": [
+    {"id": "a", "prompt": "hi", "filler_chars": 10},
+    {"id": "a", "prompt_file": "missing.txt"}
+  ],
+  "timeout": "soon",
+  "cold": -1,
+  "thresholds": [{"model": "m"}, {"max_error_rate": 2}],
+  "baseline": {"max_regression_pct": 0},
+  "load": {"concurrency": [1, 0], "duration": "long", "rate": -1}
+}`)
+
+	_, err := LoadSuite(path)
+	if err == nil {
+		t.Fatal("expected validation errors")
+	}
+	for _, want := range []string{
+		"set either base_url or hosts",
+		"models[0]: name is required",
+		"scenarios[0]: set exactly one of",
+		`scenarios[1]: duplicate id "a"`,
+		"scenarios[1]: could not read prompt file",
+		`timeout "soon"`,
+		"cold must not be negative",
+		"thresholds[0]: set at least one limit",
+		"thresholds[1]: max_error_rate must be between 0 and 1",
+		"baseline: run is required",
+		"baseline: max_regression_pct must be positive",
+		"load: concurrency[1] must be positive",
+		`load: duration "long"`,
+		"load: rate must not be negative",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error to mention %q, got:\n%v", want, err)
+		}
+	}
+
+	path = writeFile(t, dir, "typo.json", `{"base_url": "http://x", "modles": []}`)
+	if _, err := LoadSuite(path); err == nil || !strings.Contains(err.Error(), "modles") {
+		t.Fatalf("expected unknown fields to be rejected, got %v", err)
+	}
+}
+
+func TestExampleSuiteLoads(t *testing.T) {
+	if _, err := LoadSuite("../../harness.example.yaml"); err != nil {
+		t.Fatalf("example suite is invalid: %v", err)
+	}
+}
@@ -2,7 +2,10 @@
 // Package: harness
 package harness
 
-import "time"
+import (
+	"io"
+	"time"
+)
 
 // HarnessModelConfig defines how to call a specific model via Ollama.
 type HarnessModelConfig struct {
@@ -18,10 +21,19 @@ type HarnessPromptScenario struct {
 	Prompt      string `json:"prompt"`      // full prompt text
 }
 
+// HarnessHost is an Ollama endpoint the suite runs against.
+type HarnessHost struct {
+	Name string `json:"name"` // label used in results; defaults to the URL
+	URL  string `json:"url"`  // e.g. "http://localhost:11434"
+}
+
 // HarnessSuiteConfig configures the entire run.
 type HarnessSuiteConfig struct {
-	// Ollama endpoint like "http://localhost:11434"
-	BaseURL string `json:"base_url"`
+	// Ollama endpoints to benchmark; the suite runs once per host.
+	Hosts []HarnessHost `json:"hosts"`
+
+	// How many hosts are benchmarked at the same time (default 1).
+	HostParallelism int `json:"host_parallelism"`
 
 	// Models to benchmark.
 	Models []HarnessModelConfig `json:"models"`
@@ -35,19 +47,75 @@ type HarnessSuiteConfig struct {
 	// Whether to run an initial warm-up request per model (not recorded).
 	Warmup bool `json:"warmup"`
 
-	// Whether to attempt a "cold" run before warm trials (unload/restart is user-managed).
-	// If true, we tag the first run as Cold=true in TrialResult. Otherwise all are warm.
-	IncludeCold bool `json:"include_cold"`
+	// Cold starts per model, measured before the warm-up. Each unloads the
+	// model, waits until /api/ps no longer lists it, and sends the first
+	// scenario, tagged Cold=true in TrialResult.
+	ColdTrials int `json:"cold_trials"`
 
 	// HTTP timeout per request (safety guard).
 	RequestTimeout time.Duration `json:"request_timeout"`
+
+	// Absolute limits checked after the run; see checkGates.
+	Thresholds []HarnessThreshold `json:"thresholds,omitempty"`
+
+	// Allowed regression against an earlier run.
+	Baseline *HarnessBaseline `json:"baseline,omitempty"`
+
+	// Load test settings used by RunLoadSuite; nil uses the defaults.
+	Load *HarnessLoadConfig `json:"load,omitempty"`
+
+	// Where progress lines are written; nil discards them.
+	Progress io.Writer `json:"-"`
+}
+
+// HarnessThreshold limits the results of the matching host, model and
+// scenario combinations. Empty selectors match everything; unset limits are
+// not checked.
+type HarnessThreshold struct {
+	Host     string `json:"host,omitempty"`
+	Model    string `json:"model,omitempty"`
+	Scenario string `json:"scenario,omitempty"`
+
+	MaxTTFTP95   float64  `json:"max_ttft_p95_ms,omitempty"`
+	MaxTotalP95  float64  `json:"max_total_p95_ms,omitempty"`
+	MinGenTPS    float64  `json:"min_gen_tps,omitempty"`
+	MaxErrorRate *float64 `json:"max_error_rate,omitempty"` // 0..1; pointer so 0 can be required
+}
+
+// HarnessBaseline compares a run against an earlier saved run.
+type HarnessBaseline struct {
+	// Run names the earlier run as accepted by LoadResult.
+	Run string `json:"run"`
+	// MaxRegressionPct is the allowed worsening of TTFT p95, total p95 and
+	// mean gen TPS, in percent.
+	MaxRegressionPct float64 `json:"max_regression_pct"`
+}
+
+// HarnessLoadConfig configures a load test. Each concurrency level runs for
+// RampUp plus Duration; only requests started after the ramp-up are measured.
+type HarnessLoadConfig struct {
+	// Concurrency levels, run in order (default 1, 2, 4, 8). In closed-loop
+	// mode this is the number of workers; at a fixed rate, the most
+	// requests in flight.
+	Concurrency []int `json:"concurrency"`
+
+	// Measured time per level (default 30s).
+	Duration time.Duration `json:"duration"`
+
+	// Time over which workers are started or the rate is raised.
+	RampUp time.Duration `json:"ramp_up"`
+
+	// Requests per second started at each level. Zero runs closed-loop
+	// workers that send the next request as soon as the previous one is done.
+	Rate float64 `json:"rate,omitempty"`
 }
 
 // TrialHarnessTrialResultResult captures metrics for a single streamed generation trial.
 type HarnessTrialResult struct {
+	Host           string `json:"host"`
 	ModelName      string `json:"model_name"`
 	ScenarioID     string `json:"scenario_id"`
-	Cold           bool   `json:"cold"` // true if this was the initial cold run
+	Cold           bool   `json:"cold"` // true for a cold start after unloading the model
 	PromptLenChars int    `json:"prompt_len_chars"`
 	MaxTokens      int    `json:"max_tokens"` // extracted from options if set (num_predict)
 
@@ -71,23 +139,99 @@ type HarnessTrialResult struct {
 	DoneReason string `json:"done_reason,omitempty"`
 }
 
-// HarnessModelSummary aggregates per-model stats for reporting.
+// HarnessStats are the statistics of a group of trials. Failed trials only
+// count towards Errors; every other statistic covers the successful ones.
+type HarnessStats struct {
+	Trials    int     `json:"trials"` // including failed ones
+	Errors    int     `json:"errors"`
+	ErrorRate float64 `json:"error_rate"` // Errors / Trials
+
+	// Client-measured TTFT and total latency
+	TTFTP50  float64 `json:"ttft_p50_ms"`
+	TTFTP95  float64 `json:"ttft_p95_ms"`
+	TTFTP99  float64 `json:"ttft_p99_ms"`
+	TTFTMin  float64 `json:"ttft_min_ms"`
+	TTFTMax  float64 `json:"ttft_max_ms"`
+	TTFTCV   float64 `json:"ttft_cv"` // coefficient of variation, std / mean
+	TotalP50 float64 `json:"total_p50_ms"`
+	TotalP95 float64 `json:"total_p95_ms"`
+	TotalP99 float64 `json:"total_p99_ms"`
+	TotalMin float64 `json:"total_min_ms"`
+	TotalMax float64 `json:"total_max_ms"`
+	TotalCV  float64 `json:"total_cv"`
+
+	// Mean +/- std for GenTokensPerSec and PromptTokensPerSec
+	GenTPSMean    float64 `json:"gen_tps_mean"`
+	GenTPSStd     float64 `json:"gen_tps_std"`
+	GenTPSCV      float64 `json:"gen_tps_cv"`
+	PromptTPSMean float64 `json:"prompt_tps_mean"`
+	PromptTPSStd  float64 `json:"prompt_tps_std"`
+
+	// Server-reported model load time
+	LoadMean float64 `json:"load_mean_ms"`
+	LoadP50  float64 `json:"load_p50_ms"`
+	LoadP95  float64 `json:"load_p95_ms"`
+	LoadMax  float64 `json:"load_max_ms"`
+}
+
+// HarnessModelSummary aggregates per-model stats for reporting. The embedded
+// stats cover the warm trials of every scenario.
 type HarnessModelSummary struct {
+	Host      string `json:"host"`
 	ModelName string `json:"model_name"`
 
-	// p50/p95 for TTFT and Total latency across all warm trials
+	HarnessStats
+
+	// Cold trials, reported apart from the warm ones; nil without any.
+	Cold *HarnessStats `json:"cold,omitempty"`
+
+	// Warm trials per scenario, in suite order.
+	Scenarios []HarnessScenarioSummary `json:"scenarios"`
+}
+
+// HarnessScenarioSummary are the warm statistics of one model and scenario.
+type HarnessScenarioSummary struct {
+	ScenarioID string `json:"scenario_id"`
+	HarnessStats
+}
+
+// HarnessLoadLevel reports one model on one host at one concurrency level.
+type HarnessLoadLevel struct {
+	Host        string `json:"host"`
+	ModelName   string `json:"model_name"`
+	Concurrency int    `json:"concurrency"`
+
+	Requests int `json:"requests"` // measured requests that succeeded
+	Errors   int `json:"errors"`
+	Skipped  int `json:"skipped"` // fixed-rate arrivals dropped because every slot was busy
+
+	// Throughput over the measured window, all workers together.
+	WallSeconds     float64 `json:"wall_seconds"`
+	RequestsPerSec  float64 `json:"requests_per_sec"`
+	GenTokensPerSec float64 `json:"gen_tokens_per_sec"`
+
 	TTFTP50  float64 `json:"ttft_p50_ms"`
 	TTFTP95  float64 `json:"ttft_p95_ms"`
+	TTFTP99  float64 `json:"ttft_p99_ms"`
 	TotalP50 float64 `json:"total_p50_ms"`
 	TotalP95 float64 `json:"total_p95_ms"`
+	TotalP99 float64 `json:"total_p99_ms"`
+
+	// Server time not spent evaluating, see queueMillis.
+	QueueP50 float64 `json:"queue_p50_ms"`
+	QueueP95 float64 `json:"queue_p95_ms"`
+}
 
-	// Mean +/- std for GenTokensPerSec
-	GenTPSMean float64 `json:"gen_tps_mean"`
-	GenTPSStd  float64 `json:"gen_tps_std"`
+// HarnessLoadResult is the artifact returned by RunLoadSuite.
+type HarnessLoadResult struct {
+	Config      HarnessSuiteConfig `json:"config"`
+	Levels      []HarnessLoadLevel `json:"levels"`
+	GeneratedAt time.Time          `json:"generated_at"`
 }
 
 // HarnessSuiteResult is the top-level artifact returned by RunSpeedSuite.
 type HarnessSuiteResult struct {
+	RunID        string                `json:"run_id"` // set when the result is saved
 	Config       HarnessSuiteConfig    `json:"config"`
 	Trials       []HarnessTrialResult  `json:"trials"`
 	ModelReports []HarnessModelSummary `json:"model_reports"`